@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Largest accepted response body
+const maxResponseSize = 1 << 20
+
+// client, representing a client of the '/v2/' API of a Zephyr server
+type client struct {
+	baseURL *url.URL
+	apiKey  string
+	http    *http.Client
+}
+
+// serverError, representing the error envelope of the '/v2/' API
+type serverError struct {
+	Code       string `json:"code"`
+	Status     int    `json:"status"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+	RequestID  string `json:"requestId"`
+	RetryAfter string `json:"-"`
+}
+
+func (err *serverError) Error() string {
+	msg := err.Title
+	if err.Detail != "" {
+		msg = fmt.Sprintf("%s: %s", err.Title, err.Detail)
+	}
+
+	if err.RetryAfter != "" {
+		msg = fmt.Sprintf("%s(retry after %s seconds)", msg, err.RetryAfter)
+	}
+
+	return msg
+}
+
+func newClient(serverURL string, apiKey string, timeout time.Duration) (*client, error) {
+	baseURL, err := url.Parse(serverURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid server URL '%s', expected an HTTP(S) URL(e.g. 'http://127.0.0.1:3000')", serverURL)
+	}
+
+	return &client{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// get fetches an endpoint of the '/v2/' API(e.g. 'weather/Milan,IT')
+// and returns the body of successful responses as it is
+func (cl *client) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	endpoint := cl.baseURL.JoinPath("v2", path)
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if cl.apiKey != "" {
+		req.Header.Set("X-API-Key", cl.apiKey)
+	}
+
+	res, err := cl.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusOK {
+		return body, nil
+	}
+
+	// Report the error envelope of the server, if any
+	var envelope struct {
+		Error *serverError `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Error == nil {
+		return nil, fmt.Errorf("unexpected response from the server: %s", res.Status)
+	}
+
+	envelope.Error.RetryAfter = res.Header.Get("Retry-After")
+
+	return nil, envelope.Error
+}
+
+// cityPath returns the path of a located endpoint,
+// escaping city names containing spaces or slashes
+func cityPath(endpoint string, cityName string) string {
+	return endpoint + "/" + url.PathEscape(strings.TrimSpace(cityName))
+}
@@ -0,0 +1,231 @@
+// Command zephyr-cli queries a Zephyr server from the terminal, printing
+// either a human-readable summary or the JSON returned by the '/v2/' API
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+)
+
+const defaultURL = "http://127.0.0.1:3000"
+
+// command, representing a subcommand mapped to an endpoint of the '/v2/' API
+type command struct {
+	endpoint  string
+	needsCity bool
+	summary   string
+	render    func(io.Writer, []byte) error
+}
+
+var commands = map[string]command{
+	"weather":  {"weather", true, "Current weather of a city", renderWeather},
+	"forecast": {"forecast", true, "Daily forecast of a city", renderForecast},
+	"wind":     {"wind", true, "Current wind of a city", renderWind},
+	"moon":     {"moon", false, "Current moon phase", renderMoon},
+	"stats":    {"stats", true, "Statistics of the temperatures recorded for a city", renderStatistics},
+}
+
+// options, representing the flags and the arguments of an invocation
+type options struct {
+	serverURL  string
+	apiKey     string
+	isImperial bool
+	isJSON     bool
+	isWatch    bool
+	interval   time.Duration
+	timeout    time.Duration
+	cmd        command
+	cityName   string
+}
+
+func usage(out io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(out, "Usage: zephyr-cli [flags] <command> [city]")
+	fmt.Fprintln(out, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-10s%s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(out, "\nCities can be qualified as 'city,state,country'(e.g. 'Milan,IT').")
+	fmt.Fprintln(out, "\nFlags:")
+	flags.SetOutput(out)
+	flags.PrintDefaults()
+}
+
+// parseArgs parses the command line, accepting flags
+// both before and after the command and the city name
+func parseArgs(args []string, stderr io.Writer) (options, error) {
+	opts := options{}
+
+	flags := flag.NewFlagSet("zephyr-cli", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.serverURL, "url", envOr("ZEPHYR_URL", defaultURL), "URL of the Zephyr server(or 'ZEPHYR_URL')")
+	flags.StringVar(&opts.apiKey, "key", "", "API key of the server, if required(or 'ZEPHYR_API_KEY')")
+	flags.BoolVar(&opts.isImperial, "imperial", false, "Use imperial units(°F, mph, mi)")
+	flags.BoolVar(&opts.isJSON, "json", false, "Print the JSON returned by the server")
+	flags.BoolVar(&opts.isWatch, "watch", false, "Refresh the output until interrupted")
+	flags.DurationVar(&opts.interval, "interval", time.Minute, "Refresh interval of the watch mode")
+	flags.DurationVar(&opts.timeout, "timeout", 15*time.Second, "Timeout of each request")
+
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				usage(stderr, flags)
+			}
+
+			return options{}, err
+		}
+
+		if flags.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(positional) == 0 {
+		usage(stderr, flags)
+		return options{}, errors.New("missing command")
+	}
+
+	cmd, isPresent := commands[positional[0]]
+	if !isPresent {
+		return options{}, fmt.Errorf("unknown command '%s'", positional[0])
+	}
+	opts.cmd = cmd
+
+	switch {
+	case cmd.needsCity && len(positional) != 2:
+		return options{}, fmt.Errorf("usage: zephyr-cli %s <city>", positional[0])
+	case !cmd.needsCity && len(positional) != 1:
+		return options{}, fmt.Errorf("usage: zephyr-cli %s", positional[0])
+	case cmd.needsCity:
+		opts.cityName = positional[1]
+	}
+
+	// Keep the API key out of the usage message
+	if opts.apiKey == "" {
+		opts.apiKey = os.Getenv("ZEPHYR_API_KEY")
+	}
+
+	if opts.isWatch && opts.interval < time.Second {
+		return options{}, errors.New("the watch interval must be at least one second")
+	}
+
+	return opts, nil
+}
+
+func envOr(name string, fallback string) string {
+	if val := os.Getenv(name); val != "" {
+		return val
+	}
+
+	return fallback
+}
+
+// isTerminal reports whether a file is attached to a terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fetch queries the server once and prints the result
+func fetch(ctx context.Context, cl *client, opts options, out io.Writer) error {
+	path := opts.cmd.endpoint
+	if opts.cmd.needsCity {
+		path = cityPath(path, opts.cityName)
+	}
+
+	params := url.Values{}
+	if opts.isImperial {
+		params.Set("units", "imperial")
+	}
+
+	body, err := cl.get(ctx, path, params)
+	if err != nil {
+		return err
+	}
+
+	if opts.isJSON {
+		_, err := out.Write(body)
+		return err
+	}
+
+	return opts.cmd.render(out, body)
+}
+
+// run executes a command, repeating it on watch mode until the context is done.
+// While watching, errors are reported without stopping
+func run(ctx context.Context, opts options, stdout io.Writer, stderr io.Writer, clearScreen bool) error {
+	cl, err := newClient(opts.serverURL, opts.apiKey, opts.timeout)
+	if err != nil {
+		return err
+	}
+
+	if !opts.isWatch {
+		return fetch(ctx, cl, opts, stdout)
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		if clearScreen {
+			fmt.Fprint(stdout, "\033[H\033[2J")
+		}
+		if !opts.isJSON {
+			renderUpdate(stdout, time.Now())
+		}
+
+		if err := fetch(ctx, cl, opts, stdout); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(stderr, "zephyr-cli: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func main() {
+	opts, err := parseArgs(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zephyr-cli: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Stop watching on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Only redraw the screen of terminals, hence piped
+	// output keeps every update
+	clearScreen := opts.isWatch && !opts.isJSON && isTerminal(os.Stdout)
+
+	if err := run(ctx, opts, os.Stdout, os.Stderr, clearScreen); err != nil {
+		fmt.Fprintf(os.Stderr, "zephyr-cli: %v\n", err)
+		os.Exit(1)
+	}
+}
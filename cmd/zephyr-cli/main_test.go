@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer serves canned responses of the '/v2/' API
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/weather/{city}", func(res http.ResponseWriter, req *http.Request) {
+		if req.PathValue("city") != "New York,US" {
+			res.Header().Set("Content-Type", "application/json")
+			res.WriteHeader(http.StatusNotFound)
+			io.WriteString(res, `{"error":{"code":"city-not-found","status":404,"title":"City not found","detail":"Cannot find this city"}}`)
+			return
+		}
+
+		temp, feelsLike, units := "33.1", "35.6", `{"temperature":"°C","speed":"km/h","pressure":"hPa","distance":"km"}`
+		if req.URL.Query().Get("units") == "imperial" {
+			temp, feelsLike, units = "91.6", "96.1", `{"temperature":"°F","speed":"mph","pressure":"hPa","distance":"mi"}`
+		}
+
+		io.WriteString(res, `{"date":"2025-06-19T12:00:00Z","temperature":`+temp+`,"condition":"Clear","feelsLike":`+feelsLike+`,"emoji":"☀️",`+
+			`"units":`+units+`,"location":{"name":"New York","country":"US","lat":40.71,"lon":-74.01}}`)
+	})
+	mux.HandleFunc("GET /v2/moon", func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-API-Key") != "secret" {
+			res.Header().Set("Retry-After", "30")
+			res.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(res, `{"error":{"code":"rate-limited","status":429,"title":"Too many requests"}}`)
+			return
+		}
+
+		io.WriteString(res, `{"icon":"🌔","phase":"Waxing Gibbous","percentage":82}`+"\n")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestParseArgs(t *testing.T) {
+	opts, err := parseArgs([]string{"-json", "weather", "Milan,IT", "-imperial"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !opts.isJSON || !opts.isImperial || opts.cityName != "Milan,IT" || opts.cmd.endpoint != "weather" {
+		t.Errorf("Got %+v", opts)
+	}
+
+	for _, args := range [][]string{{}, {"rain"}, {"weather"}, {"moon", "Milan"}, {"-watch", "-interval", "1ms", "moon"}} {
+		if _, err := parseArgs(args, io.Discard); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		Name     string
+		Args     []string
+		Expected string
+		Err      string
+	}{
+		{"Pretty output", []string{"weather", "New York,US", "-imperial"}, "📍 New York, US\n☀️  Clear, 91.6°F(feels like 96.1°F)\n", ""},
+		{"JSON passthrough", []string{"-json", "-key", "secret", "moon"}, `{"icon":"🌔","phase":"Waxing Gibbous","percentage":82}` + "\n", ""},
+		{"Server error", []string{"weather", "Atlantis"}, "", "City not found: Cannot find this city"},
+		{"Rate limit", []string{"moon"}, "", "Too many requests(retry after 30 seconds)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			opts, err := parseArgs(append([]string{"-url", server.URL}, test.Args...), io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			err = run(context.Background(), opts, &out, io.Discard, false)
+
+			if test.Err != "" {
+				if err == nil || err.Error() != test.Err {
+					t.Errorf("Got %v, wanted %s", err, test.Err)
+				}
+				return
+			}
+
+			if err != nil || !strings.HasPrefix(out.String(), test.Expected) {
+				t.Errorf("Got (%q, %v), wanted %q", out.String(), err, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Layout of the dates printed on the terminal(e.g. 'Thursday, 2025/06/19')
+const dateLayout = "Monday, 2006/01/02"
+
+// fmtLocation formats the location resolved by the server, if any
+func fmtLocation(location *types.City) string {
+	if location == nil {
+		return ""
+	}
+
+	if location.State != "" {
+		return fmt.Sprintf("📍 %s, %s, %s\n", location.Name, location.State, location.Country)
+	}
+
+	return fmt.Sprintf("📍 %s, %s\n", location.Name, location.Country)
+}
+
+func renderWeather(out io.Writer, body []byte) error {
+	var weather types.WeatherV2
+	if err := json.Unmarshal(body, &weather); err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, fmtLocation(weather.Location))
+	fmt.Fprintf(out, "%s  %s, %.1f%s(feels like %.1f%s)\n",
+		weather.Emoji, weather.Condition,
+		weather.Temperature, weather.Units.Temperature,
+		weather.FeelsLike, weather.Units.Temperature,
+	)
+	fmt.Fprintf(out, "🕒 %s\n", weather.Date.Local().Format(dateLayout+" 15:04"))
+
+	return nil
+}
+
+func renderWind(out io.Writer, body []byte) error {
+	var wind types.CurrentWindV2
+	if err := json.Unmarshal(body, &wind); err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, fmtLocation(wind.Location))
+	fmt.Fprintf(out, "%s  %s, %.1f %s\n", wind.Arrow, wind.Direction, wind.Speed, wind.Units.Speed)
+
+	return nil
+}
+
+func renderForecast(out io.Writer, body []byte) error {
+	var forecast types.ForecastV2
+	if err := json.Unmarshal(body, &forecast); err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, fmtLocation(forecast.Location))
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "DATE\t\tCONDITION\tMIN\tMAX\tFEELS LIKE\tWIND")
+	for _, day := range forecast.Forecast {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%.1f%s\t%.1f%s\t%.1f%s\t%s %.1f %s\n",
+			day.Date.Local().Format(dateLayout), day.Emoji, day.Condition,
+			day.Min, forecast.Units.Temperature,
+			day.Max, forecast.Units.Temperature,
+			day.FeelsLike, forecast.Units.Temperature,
+			day.Wind.Arrow, day.Wind.Speed, forecast.Units.Speed,
+		)
+	}
+
+	return table.Flush()
+}
+
+func renderMoon(out io.Writer, body []byte) error {
+	var moon types.MoonV2
+	if err := json.Unmarshal(body, &moon); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s  %s(%d%%)\n", moon.Icon, moon.Phase, moon.Percentage)
+
+	return nil
+}
+
+func renderStatistics(out io.Writer, body []byte) error {
+	var stats types.StatResultV2
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return err
+	}
+
+	unit := stats.Units.Temperature
+
+	fmt.Fprint(out, fmtLocation(stats.Location))
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Samples\t%d\n", stats.Count)
+	fmt.Fprintf(table, "Min\t%.1f%s\n", stats.Min, unit)
+	fmt.Fprintf(table, "Max\t%.1f%s\n", stats.Max, unit)
+	fmt.Fprintf(table, "Mean\t%.1f%s\n", stats.Mean, unit)
+	fmt.Fprintf(table, "Std. dev.\t%.2f%s\n", stats.StdDev, unit)
+	fmt.Fprintf(table, "Median\t%.1f%s\n", stats.Median, unit)
+	fmt.Fprintf(table, "Mode\t%.1f%s\n", stats.Mode, unit)
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(stats.Anomalies) == 0 {
+		fmt.Fprintln(out, "No anomalies")
+		return nil
+	}
+
+	fmt.Fprintln(out, "⚠️  Anomalies:")
+	for _, anomaly := range stats.Anomalies {
+		fmt.Fprintf(out, "   %s  %.1f%s\n", anomaly.Date.Local().Format(dateLayout), anomaly.Temperature, unit)
+	}
+
+	return nil
+}
+
+// renderUpdate prints the time of an update in watch mode
+func renderUpdate(out io.Writer, now time.Time) {
+	fmt.Fprintf(out, "── Updated at %s ──\n", now.Format(time.TimeOnly))
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Duration, representing a time.Duration expressed as a Go duration string(e.g. '30m')
+type Duration time.Duration
+
+func (duration *Duration) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err != nil {
+		return fmt.Errorf("expected a duration string(e.g. \"30m\")")
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration '%s'", value)
+	}
+	*duration = Duration(parsed)
+
+	return nil
+}
+
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(duration).String())
+}
+
+func (duration Duration) String() string {
+	return time.Duration(duration).String()
+}
+
+// TLSConfig, representing the certificate used to serve HTTPS
+type TLSConfig struct {
+	CertFile     string `json:"certFile"`
+	KeyFile      string `json:"keyFile"`
+	ClientCAFile string `json:"clientCaFile"` // Enables mutual TLS
+}
+
+// ListenConfig, representing the settings of the HTTP and gRPC servers
+type ListenConfig struct {
+	Address       string    `json:"address"`
+	GRPCAddress   string    `json:"grpcAddress"`   // Empty disables the gRPC server
+	HealthAddress string    `json:"healthAddress"` // Plain HTTP health endpoints, empty disables them
+	TLS           TLSConfig `json:"tls"`
+}
+
+// BreakerConfig, representing the settings of the circuit breakers
+type BreakerConfig struct {
+	Threshold int      `json:"threshold"`
+	Cooldown  Duration `json:"cooldown"`
+}
+
+// ProviderConfig, representing the settings of the upstream provider
+type ProviderConfig struct {
+	Token      string        `json:"token"`
+	BaseURL    string        `json:"baseUrl"`
+	Timeout    Duration      `json:"timeout"`
+	MaxRetries int           `json:"maxRetries"`
+	Breaker    BreakerConfig `json:"breaker"`
+}
+
+// QuotaConfig, representing the daily quota of upstream calls
+type QuotaConfig struct {
+	Soft uint `json:"soft"`
+	Hard uint `json:"hard"`
+}
+
+// TTLConfig, representing the cache time-to-live of each data kind
+type TTLConfig struct {
+	Weather   Duration `json:"weather"`
+	Metrics   Duration `json:"metrics"`
+	Wind      Duration `json:"wind"`
+	Forecast  Duration `json:"forecast"`
+	Moon      Duration `json:"moon"`
+	Geocoding Duration `json:"geocoding"`
+}
+
+// WatchConfig, representing the cities whose weather is periodically
+// fetched to keep the statistical database up to date
+type WatchConfig struct {
+	Cities   []string `json:"cities"`
+	Interval Duration `json:"interval"`
+}
+
+// RateLimitConfig, representing the rate limits of clients
+type RateLimitConfig struct {
+	IP         uint `json:"ip"`  // Requests per minute of anonymous clients
+	Key        uint `json:"key"` // Default requests per minute of API keys
+	RequireKey bool `json:"requireKey"`
+	TrustProxy bool `json:"trustProxy"`
+}
+
+// CORSConfig, representing the origins allowed to call the API from a browser
+type CORSConfig struct {
+	AllowedOrigins []string `json:"allowedOrigins"` // '*' allows every origin
+	MaxAge         Duration `json:"maxAge"`         // Lifetime of preflight responses
+}
+
+// CompressionConfig, representing the settings of response compression
+type CompressionConfig struct {
+	Enabled bool `json:"enabled"`
+	MinSize int  `json:"minSize"` // Bytes below which responses are sent uncompressed
+}
+
+// StorageConfig, representing the paths of persistent data
+type StorageConfig struct {
+	DataDir  string `json:"dataDir"`
+	KeysFile string `json:"keysFile"` // Defaults to 'keys.json' within the data directory
+}
+
+// Config, representing the whole configuration of the service
+type Config struct {
+	Listen          ListenConfig      `json:"listen"`
+	Provider        ProviderConfig    `json:"provider"`
+	Quota           QuotaConfig       `json:"quota"`
+	TTL             TTLConfig         `json:"ttl"`
+	Watch           WatchConfig       `json:"watch"`
+	RateLimit       RateLimitConfig   `json:"rateLimit"`
+	CORS            CORSConfig        `json:"cors"`
+	Compression     CompressionConfig `json:"compression"`
+	Storage         StorageConfig     `json:"storage"`
+	AdminToken      string            `json:"adminToken"`
+	StrictGeocoding bool              `json:"strictGeocoding"`
+	LogLevel        string            `json:"logLevel"`
+}
+
+const (
+	MinTTL = time.Minute
+	MaxTTL = 30 * 24 * time.Hour
+)
+
+// Default returns the default configuration
+func Default() Config {
+	return Config{
+		Listen: ListenConfig{
+			Address: ":3000",
+		},
+		Provider: ProviderConfig{
+			BaseURL:    "https://api.openweathermap.org",
+			Timeout:    Duration(5 * time.Second),
+			MaxRetries: 2,
+			Breaker: BreakerConfig{
+				Threshold: 5,
+				Cooldown:  Duration(30 * time.Second),
+			},
+		},
+		// By default, stay within OpenWeatherMap's free tier(1,000 calls/day)
+		Quota: QuotaConfig{
+			Soft: 900,
+			Hard: 1000,
+		},
+		TTL: TTLConfig{
+			Weather:  Duration(30 * time.Minute),
+			Metrics:  Duration(30 * time.Minute),
+			Wind:     Duration(10 * time.Minute),
+			Forecast: Duration(3 * time.Hour),
+			Moon:     Duration(12 * time.Hour),
+			// City coordinates never change
+			Geocoding: Duration(MaxTTL),
+		},
+		Watch: WatchConfig{
+			Interval: Duration(time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			IP:  60,
+			Key: 120,
+		},
+		CORS: CORSConfig{
+			MaxAge: Duration(10 * time.Minute),
+		},
+		Compression: CompressionConfig{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		Storage: StorageConfig{
+			DataDir: "data",
+		},
+		LogLevel: "info",
+	}
+}
+
+// Error, representing every problem found while loading a configuration
+type Error struct {
+	Problems []string
+}
+
+func (err *Error) Error() string {
+	return "Invalid configuration:\n  - " + strings.Join(err.Problems, "\n  - ")
+}
+
+// Load reads the configuration file(if any), applies the environment
+// variables on top of it and validates the result
+func Load(path string) (Config, error) {
+	config := Default()
+
+	if path != "" {
+		if err := config.readFile(path); err != nil {
+			return config, &Error{Problems: []string{err.Error()}}
+		}
+	}
+
+	problems := config.applyEnv()
+	problems = append(problems, config.Validate()...)
+	if len(problems) > 0 {
+		return config, &Error{Problems: problems}
+	}
+
+	return config, nil
+}
+
+func (config *Config) readFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read '%s': %v", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(config); err != nil {
+		var (
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+
+		switch {
+		case errors.As(err, &syntaxErr):
+			line, column := position(content, syntaxErr.Offset)
+			return fmt.Errorf("%s:%d:%d: %v", path, line, column, syntaxErr)
+		case errors.As(err, &typeErr):
+			line, column := position(content, typeErr.Offset)
+			return fmt.Errorf("%s:%d:%d: '%s' must be a %s, not a %s", path, line, column, typeErr.Field, typeErr.Type, typeErr.Value)
+		default:
+			// e.g. unknown fields or invalid durations
+			return fmt.Errorf("%s: %s", path, strings.TrimPrefix(err.Error(), "json: "))
+		}
+	}
+
+	return nil
+}
+
+// position converts a byte offset into a line and a column
+func position(content []byte, offset int64) (int, int) {
+	offset = min(offset, int64(len(content)))
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// KeysFile returns the path of the API keys file
+func (config *Config) KeysFile() string {
+	if config.Storage.KeysFile != "" {
+		return config.Storage.KeysFile
+	}
+
+	return filepath.Join(config.Storage.DataDir, "keys.json")
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "zephyr.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{
+		"listen": { "address": ":8080" },
+		"provider": { "token": "file-token" },
+		"ttl": { "wind": "15m" },
+		"watch": { "cities": ["Milan,IT"] }
+	}`)
+
+	// Environment variables take precedence over the file
+	t.Setenv("ZEPHYR_TOKEN", "env-token")
+	t.Setenv("ZEPHYR_TTL_MOON", "6h")
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("Got %v, wanted nil", err)
+	}
+
+	tests := []struct {
+		Name     string
+		Got      any
+		Expected any
+	}{
+		{"Listen address", config.Listen.Address, ":8080"},
+		{"Token", config.Provider.Token, "env-token"},
+		{"File TTL", time.Duration(config.TTL.Wind), 15 * time.Minute},
+		{"Env TTL", time.Duration(config.TTL.Moon), 6 * time.Hour},
+		{"Default TTL", time.Duration(config.TTL.Weather), 30 * time.Minute},
+		{"Watched cities", len(config.Watch.Cities), 1},
+		{"Keys file", config.KeysFile(), filepath.Join("data", "keys.json")},
+		{"Strict geocoding", config.StrictGeocoding, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Got != test.Expected {
+				t.Errorf("Got %v, wanted %v", test.Got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Content  string
+		Expected []string
+	}{
+		{"Unknown field", `{"listen": {"adress": ":80"}}`, []string{`unknown field "adress"`}},
+		{"Wrong type", "{\n\"quota\": {\"soft\": \"x\"}\n}", []string{":2:", "'quota.soft' must be a uint"}},
+		{"Invalid values", `{
+			"provider": {"token": "token"},
+			"quota": {"soft": 2000},
+			"ttl": {"weather": "10s"},
+			"logLevel": "verbose"
+		}`, []string{"quota.soft: must not exceed", "ttl.weather: time-to-live", "logLevel: invalid level"}},
+		{"Health address", `{
+			"provider": {"token": "token"},
+			"listen": {"address": ":3000", "healthAddress": ":3000"}
+		}`, []string{"listen.healthAddress: must differ from 'listen.address'"}},
+		{"Watch interval without cities", `{
+			"provider": {"token": "token"},
+			"watch": {"interval": "0s"}
+		}`, []string{"watch.interval: must be at least"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, test.Content))
+			if err == nil {
+				t.Fatalf("Got nil, wanted an error")
+			}
+
+			for _, expected := range test.Expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Got %v, wanted it to contain %q", err, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	old.Provider.Token = "old-token"
+
+	new := Default()
+	new.Provider.Token = "new-token"
+	new.TTL.Wind = Duration(time.Hour)
+	new.Listen.Address = ":8080"
+	new.Watch.Cities = []string{"Milan,IT"}
+
+	tests := []Change{
+		{Field: "listen.address", Old: `":3000"`, New: `":8080"`, Restart: true},
+		{Field: "provider.token", Old: `"<redacted, 9 characters>"`, New: `"<redacted, 9 characters>"`},
+		{Field: "ttl.wind", Old: `"10m0s"`, New: `"1h0m0s"`},
+		{Field: "watch.cities", Old: "null", New: `["Milan,IT"]`},
+	}
+
+	got := Diff(old, new)
+	if len(got) != len(tests) {
+		t.Fatalf("Got %v, wanted %v", got, tests)
+	}
+
+	for idx, expected := range tests {
+		t.Run(expected.Field, func(t *testing.T) {
+			if got[idx] != expected {
+				t.Errorf("Got %v, wanted %v", got[idx], expected)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := writeConfig(t, `{}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	go Watch(ctx, path, 10*time.Millisecond, func() {
+		changed <- struct{}{}
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{ "logLevel": "debug" }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Got no change, wanted one")
+	}
+}
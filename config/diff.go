@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change, representing a configuration field whose value has changed
+type Change struct {
+	Field   string `json:"field"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Restart bool   `json:"restart"` // Whether the change requires a restart
+}
+
+// Fields holding secrets, never reported in clear
+var secretFields = map[string]bool{
+	"provider.token": true,
+	"adminToken":     true,
+}
+
+// Fields that cannot be changed without restarting the service
+var restartPrefixes = []string{
+	"listen.",
+	"compression.",
+	"storage.",
+	"provider.baseUrl",
+	"provider.timeout",
+	"provider.maxRetries",
+}
+
+// flatten converts a configuration into a map of dotted field paths(e.g. 'ttl.wind')
+func flatten(config Config) map[string]string {
+	content, _ := json.Marshal(config)
+
+	var tree map[string]any
+	json.Unmarshal(content, &tree)
+
+	fields := make(map[string]string)
+	var walk func(prefix string, node any)
+	walk = func(prefix string, node any) {
+		if object, isObject := node.(map[string]any); isObject {
+			for key, val := range object {
+				walk(prefix+key+".", val)
+			}
+			return
+		}
+
+		value, _ := json.Marshal(node)
+		fields[strings.TrimSuffix(prefix, ".")] = string(value)
+	}
+	walk("", tree)
+
+	return fields
+}
+
+// Diff returns the fields that differ between two configurations
+func Diff(old Config, new Config) []Change {
+	oldFields, newFields := flatten(old), flatten(new)
+
+	var changes []Change
+	for field, newValue := range newFields {
+		oldValue := oldFields[field]
+		if oldValue == newValue {
+			continue
+		}
+
+		change := Change{Field: field, Old: oldValue, New: newValue}
+		if secretFields[field] {
+			change.Old, change.New = redact(oldValue), redact(newValue)
+		}
+
+		for _, prefix := range restartPrefixes {
+			if strings.HasPrefix(field, prefix) {
+				change.Restart = true
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+func redact(value string) string {
+	if value == `""` {
+		return value
+	}
+
+	return fmt.Sprintf(`"<redacted, %d characters>"`, len(value)-2)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Structure representing a configuration field that can be overridden
+// through an environment variable
+type envField struct {
+	name  string
+	apply func(value string) error
+}
+
+func envString(field *string) func(string) error {
+	return func(value string) error {
+		*field = value
+		return nil
+	}
+}
+
+func envBool(field *bool) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean '%s'", value)
+		}
+		*field = parsed
+
+		return nil
+	}
+}
+
+func envUint(field *uint) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+		*field = uint(parsed)
+
+		return nil
+	}
+}
+
+func envInt(field *int) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+		*field = int(parsed)
+
+		return nil
+	}
+}
+
+func envDuration(field *Duration) func(string) error {
+	return func(value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration '%s'", value)
+		}
+		*field = Duration(parsed)
+
+		return nil
+	}
+}
+
+func envList(field *[]string) func(string) error {
+	return func(value string) error {
+		// Items are separated by semicolons, since commas
+		// separate the city name from the country
+		var list []string
+		for _, item := range strings.Split(value, ";") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field = list
+
+		return nil
+	}
+}
+
+// applyEnv overrides the configuration with the environment variables,
+// returning the problems found while parsing them
+func (config *Config) applyEnv() []string {
+	var problems []string
+
+	// The legacy 'ZEPHYR_CACHE_TTL' variable(expressed in hours) overrides
+	// the value of every weather data kind, while per-endpoint
+	// variables take precedence over it
+	if value := os.Getenv("ZEPHYR_CACHE_TTL"); value != "" {
+		hours, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || hours == 0 {
+			problems = append(problems, fmt.Sprintf("ZEPHYR_CACHE_TTL: invalid number of hours '%s'", value))
+		} else {
+			legacy := Duration(time.Duration(hours) * time.Hour)
+			ttl := &config.TTL
+			ttl.Weather, ttl.Metrics, ttl.Wind, ttl.Forecast, ttl.Moon = legacy, legacy, legacy, legacy, legacy
+		}
+	}
+
+	// The legacy 'ZEPHYR_PORT' variable only sets the listen port
+	if value := os.Getenv("ZEPHYR_PORT"); value != "" {
+		config.Listen.Address = ":" + value
+	}
+
+	fields := []envField{
+		{"ZEPHYR_LISTEN_ADDRESS", envString(&config.Listen.Address)},
+		{"ZEPHYR_GRPC_ADDRESS", envString(&config.Listen.GRPCAddress)},
+		{"ZEPHYR_HEALTH_ADDRESS", envString(&config.Listen.HealthAddress)},
+		{"ZEPHYR_TLS_CERT_FILE", envString(&config.Listen.TLS.CertFile)},
+		{"ZEPHYR_TLS_KEY_FILE", envString(&config.Listen.TLS.KeyFile)},
+		{"ZEPHYR_TLS_CLIENT_CA_FILE", envString(&config.Listen.TLS.ClientCAFile)},
+		{"ZEPHYR_TOKEN", envString(&config.Provider.Token)},
+		{"ZEPHYR_PROVIDER_URL", envString(&config.Provider.BaseURL)},
+		{"ZEPHYR_PROVIDER_TIMEOUT", envDuration(&config.Provider.Timeout)},
+		{"ZEPHYR_PROVIDER_RETRIES", envInt(&config.Provider.MaxRetries)},
+		{"ZEPHYR_BREAKER_THRESHOLD", envInt(&config.Provider.Breaker.Threshold)},
+		{"ZEPHYR_BREAKER_COOLDOWN", envDuration(&config.Provider.Breaker.Cooldown)},
+		{"ZEPHYR_QUOTA_SOFT", envUint(&config.Quota.Soft)},
+		{"ZEPHYR_QUOTA_HARD", envUint(&config.Quota.Hard)},
+		{"ZEPHYR_TTL_WEATHER", envDuration(&config.TTL.Weather)},
+		{"ZEPHYR_TTL_METRICS", envDuration(&config.TTL.Metrics)},
+		{"ZEPHYR_TTL_WIND", envDuration(&config.TTL.Wind)},
+		{"ZEPHYR_TTL_FORECAST", envDuration(&config.TTL.Forecast)},
+		{"ZEPHYR_TTL_MOON", envDuration(&config.TTL.Moon)},
+		{"ZEPHYR_TTL_GEOCODING", envDuration(&config.TTL.Geocoding)},
+		{"ZEPHYR_WATCH_CITIES", envList(&config.Watch.Cities)},
+		{"ZEPHYR_WATCH_INTERVAL", envDuration(&config.Watch.Interval)},
+		{"ZEPHYR_RATE_LIMIT_IP", envUint(&config.RateLimit.IP)},
+		{"ZEPHYR_RATE_LIMIT_KEY", envUint(&config.RateLimit.Key)},
+		{"ZEPHYR_REQUIRE_KEY", envBool(&config.RateLimit.RequireKey)},
+		{"ZEPHYR_TRUST_PROXY", envBool(&config.RateLimit.TrustProxy)},
+		{"ZEPHYR_CORS_ORIGINS", envList(&config.CORS.AllowedOrigins)},
+		{"ZEPHYR_CORS_MAX_AGE", envDuration(&config.CORS.MaxAge)},
+		{"ZEPHYR_COMPRESSION", envBool(&config.Compression.Enabled)},
+		{"ZEPHYR_COMPRESSION_MIN_SIZE", envInt(&config.Compression.MinSize)},
+		{"ZEPHYR_DATA_DIR", envString(&config.Storage.DataDir)},
+		{"ZEPHYR_KEYS_FILE", envString(&config.Storage.KeysFile)},
+		{"ZEPHYR_ADMIN_TOKEN", envString(&config.AdminToken)},
+		{"ZEPHYR_STRICT_GEOCODING", envBool(&config.StrictGeocoding)},
+		{"ZEPHYR_LOG_LEVEL", envString(&config.LogLevel)},
+	}
+
+	for _, field := range fields {
+		value := os.Getenv(field.name)
+		if value == "" {
+			continue
+		}
+
+		if err := field.apply(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", field.name, err))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the whole configuration, returning
+// a human-readable description of each problem
+func (config *Config) Validate() []string {
+	var problems []string
+	problem := func(field string, format string, args ...any) {
+		problems = append(problems, field+": "+fmt.Sprintf(format, args...))
+	}
+
+	// Listen address and TLS
+	if _, port, err := net.SplitHostPort(config.Listen.Address); err != nil {
+		problem("listen.address", "invalid address '%s', expected 'HOST:PORT' or ':PORT'", config.Listen.Address)
+	} else if parsed, err := strconv.ParseUint(port, 10, 16); err != nil || parsed == 0 {
+		problem("listen.address", "invalid port '%s'", port)
+	}
+
+	// Optional listeners
+	for _, listener := range []struct {
+		field   string
+		address string
+	}{
+		{"listen.grpcAddress", config.Listen.GRPCAddress},
+		{"listen.healthAddress", config.Listen.HealthAddress},
+	} {
+		if listener.address == "" {
+			continue
+		}
+
+		if _, port, err := net.SplitHostPort(listener.address); err != nil {
+			problem(listener.field, "invalid address '%s', expected 'HOST:PORT' or ':PORT'", listener.address)
+		} else if parsed, err := strconv.ParseUint(port, 10, 16); err != nil || parsed == 0 {
+			problem(listener.field, "invalid port '%s'", port)
+		} else if listener.address == config.Listen.Address {
+			problem(listener.field, "must differ from 'listen.address'")
+		}
+	}
+	if address := config.Listen.HealthAddress; address != "" && address == config.Listen.GRPCAddress {
+		problem("listen.healthAddress", "must differ from 'listen.grpcAddress'")
+	}
+
+	tls := config.Listen.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		problem("listen.tls", "both 'certFile' and 'keyFile' must be set to enable TLS")
+	}
+	if tls.ClientCAFile != "" && tls.CertFile == "" {
+		problem("listen.tls.clientCaFile", "mutual TLS requires 'certFile' and 'keyFile'")
+	}
+	for field, path := range map[string]string{
+		"listen.tls.certFile":     tls.CertFile,
+		"listen.tls.keyFile":      tls.KeyFile,
+		"listen.tls.clientCaFile": tls.ClientCAFile,
+	} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			problem(field, "cannot read '%s'", path)
+		}
+	}
+
+	// Upstream provider
+	provider := config.Provider
+	if provider.Token == "" {
+		problem("provider.token", "an OpenWeatherMap API key is required(set 'ZEPHYR_TOKEN')")
+	}
+	if parsed, err := url.Parse(provider.BaseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		problem("provider.baseUrl", "invalid URL '%s'", provider.BaseURL)
+	}
+	if provider.Timeout <= 0 {
+		problem("provider.timeout", "must be greater than zero")
+	}
+	if provider.MaxRetries < 0 || provider.MaxRetries > 10 {
+		problem("provider.maxRetries", "must be between 0 and 10")
+	}
+	if provider.Breaker.Threshold <= 0 {
+		problem("provider.breaker.threshold", "must be greater than zero")
+	}
+	if provider.Breaker.Cooldown <= 0 {
+		problem("provider.breaker.cooldown", "must be greater than zero")
+	}
+
+	// Daily quota
+	if config.Quota.Soft == 0 {
+		problem("quota.soft", "must be greater than zero")
+	}
+	if config.Quota.Hard == 0 {
+		problem("quota.hard", "must be greater than zero")
+	}
+	if config.Quota.Soft > config.Quota.Hard {
+		problem("quota.soft", "must not exceed the hard limit(%d > %d)", config.Quota.Soft, config.Quota.Hard)
+	}
+
+	// Cache time-to-live
+	ttls := []struct {
+		field string
+		value Duration
+	}{
+		{"ttl.weather", config.TTL.Weather},
+		{"ttl.metrics", config.TTL.Metrics},
+		{"ttl.wind", config.TTL.Wind},
+		{"ttl.forecast", config.TTL.Forecast},
+		{"ttl.moon", config.TTL.Moon},
+		{"ttl.geocoding", config.TTL.Geocoding},
+	}
+
+	for _, ttl := range ttls {
+		if time.Duration(ttl.value) < MinTTL || time.Duration(ttl.value) > MaxTTL {
+			problem(ttl.field, "time-to-live must be between %s and %s, got %s", MinTTL, MaxTTL, ttl.value)
+		}
+	}
+
+	// Watched cities
+	for idx, city := range config.Watch.Cities {
+		if strings.TrimSpace(city) == "" {
+			problem(fmt.Sprintf("watch.cities[%d]", idx), "empty city name")
+		}
+	}
+	// The interval is required even without cities, since
+	// they can be added by reloading the configuration
+	if time.Duration(config.Watch.Interval) < MinTTL {
+		problem("watch.interval", "must be at least %s", MinTTL)
+	}
+
+	// Cross-origin requests
+	for idx, origin := range config.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if parsed, err := url.Parse(origin); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.Path != "" {
+			problem(fmt.Sprintf("cors.allowedOrigins[%d]", idx), "invalid origin '%s', expected 'SCHEME://HOST[:PORT]' or '*'", origin)
+		}
+	}
+	if config.CORS.MaxAge < 0 {
+		problem("cors.maxAge", "must not be negative")
+	}
+
+	// Compression
+	if config.Compression.MinSize < 0 {
+		problem("compression.minSize", "must not be negative")
+	}
+
+	// Storage and logging
+	if config.Storage.DataDir == "" {
+		problem("storage.dataDir", "must not be empty")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		problem("logLevel", "invalid level '%s', expected 'debug', 'info', 'warn' or 'error'", config.LogLevel)
+	}
+
+	return problems
+}
+
+// Level returns the configured log level
+func (config *Config) Level() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(config.LogLevel))
+
+	return level
+}
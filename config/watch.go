@@ -0,0 +1,44 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch polls the configuration file and calls onChange whenever
+// its modification time or its size changes, until ctx is done
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+
+		return info.ModTime(), info.Size()
+	}
+
+	lastModTime, lastSize := stat()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		modTime, size := stat()
+		// Ignore files being replaced(e.g. by editors writing a temporary file)
+		if size < 0 {
+			continue
+		}
+
+		if !modTime.Equal(lastModTime) || size != lastSize {
+			lastModTime, lastSize = modTime, size
+			onChange()
+		}
+	}
+}
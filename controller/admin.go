@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Structure representing the state of a single cache
+type cacheReport struct {
+	TTL     string                 `json:"ttl"`
+	Stats   types.CacheStats       `json:"stats"`
+	Entries []types.CacheEntryInfo `json:"entries"`
+}
+
+func isAuthorized(req *http.Request, adminToken string) bool {
+	// Admin endpoints are disabled when no admin token has been configured
+	if adminToken == "" {
+		return false
+	}
+
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// GetCacheReport reports the state of the caches('/admin/cache/:kind')
+func GetCacheReport(res http.ResponseWriter, req *http.Request, caches *types.Caches, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	kinds := caches.Kinds(vars.TimeToLive)
+
+	switch kind := req.PathValue("kind"); kind {
+	case "":
+		// Report every cache
+		report := make(map[string]cacheReport, len(kinds))
+		for name, val := range kinds {
+			report[name] = cacheReport{
+				TTL:     val.TTL.String(),
+				Stats:   val.Cache.Stats(),
+				Entries: val.Cache.Entries(val.TTL),
+			}
+		}
+
+		jsonValue(res, report)
+	case "stats":
+		// Report usage counters only
+		stats := make(map[string]types.CacheStats, len(kinds))
+		for name, val := range kinds {
+			stats[name] = val.Cache.Stats()
+		}
+
+		jsonValue(res, stats)
+	default:
+		val, isPresent := kinds[kind]
+		if !isPresent {
+			jsonProblem(res, req, notFound("unknown-cache", "Unknown cache"))
+			return
+		}
+
+		jsonValue(res, cacheReport{
+			TTL:     val.TTL.String(),
+			Stats:   val.Cache.Stats(),
+			Entries: val.Cache.Entries(val.TTL),
+		})
+	}
+}
+
+// PurgeCache purges the caches, a single cache or a single entry('/admin/cache/:kind/:key')
+func PurgeCache(res http.ResponseWriter, req *http.Request, caches *types.Caches, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	kinds := caches.Kinds(vars.TimeToLive)
+	kind, key := req.PathValue("kind"), req.PathValue("key")
+
+	// Purge every cache
+	if kind == "" {
+		purged := 0
+		for _, val := range kinds {
+			purged += val.Cache.Purge()
+		}
+
+		jsonValue(res, map[string]int{"purged": purged})
+		return
+	}
+
+	val, isPresent := kinds[kind]
+	if !isPresent {
+		jsonProblem(res, req, notFound("unknown-cache", "Unknown cache"))
+		return
+	}
+
+	// Purge a whole cache
+	if key == "" {
+		jsonValue(res, map[string]int{"purged": val.Cache.Purge()})
+		return
+	}
+
+	// Purge a single key
+	if !val.Cache.PurgeKey(fmtKey(key)) {
+		jsonProblem(res, req, notFound("unknown-key", "Cache key not found"))
+		return
+	}
+
+	jsonValue(res, map[string]int{"purged": 1})
+}
+
+func AdminQuota(res http.ResponseWriter, req *http.Request, budget *types.Budget, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	jsonValue(res, budget.Usage())
+}
+
+// Structure representing the request body of a new API key
+type keyRequest struct {
+	Name  string `json:"name"`
+	Rate  uint   `json:"rate"`
+	Burst uint   `json:"burst"`
+}
+
+// ListKeys lists every API key('/admin/keys')
+func ListKeys(res http.ResponseWriter, req *http.Request, keys *types.KeyStore, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	jsonValue(res, keys.List())
+}
+
+// CreateKey creates a new API key('/admin/keys')
+func CreateKey(res http.ResponseWriter, req *http.Request, keys *types.KeyStore, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	var body keyRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(res, req.Body, 4096))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil || body.Name == "" {
+		jsonProblem(res, req, badRequest("Expected a JSON object with a 'name' field and optional 'rate' and 'burst' fields"))
+		return
+	}
+
+	key, secret, err := keys.Create(body.Name, body.Rate, body.Burst)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	// The secret is returned only once
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusCreated)
+	json.NewEncoder(res).Encode(map[string]any{
+		"key":    secret,
+		"apiKey": key,
+	})
+}
+
+// DeleteKey revokes an API key('/admin/keys/:id')
+func DeleteKey(res http.ResponseWriter, req *http.Request, keys *types.KeyStore, vars *types.Variables) {
+	if !isAuthorized(req, vars.AdminToken) {
+		jsonProblem(res, req, errUnauthorized)
+		return
+	}
+
+	deleted, err := keys.Delete(req.PathValue("id"))
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	if !deleted {
+		jsonProblem(res, req, notFound("unknown-key", "API key not found"))
+		return
+	}
+
+	jsonValue(res, map[string]int{"deleted": 1})
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAge returns how long a value fetched at the given time is still fresh,
+// in whole seconds. Stale values(e.g. served while the upstream provider
+// is unavailable) must be revalidated on every request
+func maxAge(fetchedAt time.Time, ttl time.Duration) int {
+	remaining := ttl - time.Since(fetchedAt)
+	if remaining <= 0 {
+		return 0
+	}
+
+	return int(remaining.Seconds())
+}
+
+// isNotModified evaluates the conditional headers of a request against the
+// current representation, where 'If-None-Match' takes precedence over 'If-Modified-Since'
+func isNotModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := req.Header.Values("If-None-Match"); len(ifNoneMatch) > 0 {
+		for _, candidate := range strings.Split(strings.Join(ifNoneMatch, ","), ",") {
+			// Weak comparison, as required for 'If-None-Match'
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
+
+// jsonCached writes a value built from cached data along with its HTTP caching
+// headers: a strong ETag over the body, the time the value has been fetched and
+// its remaining time-to-live. Conditional requests matching the current
+// representation are answered with '304 Not Modified', while range requests
+// are ignored since a fragment of a JSON document is useless to clients
+func jsonCached(res http.ResponseWriter, req *http.Request, val any, fetchedAt time.Time, ttl time.Duration) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(val); err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	digest := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(digest[:16]) + `"`
+	lastModified := fetchedAt.UTC().Truncate(time.Second)
+
+	res.Header().Set("ETag", etag)
+	res.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	res.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge(fetchedAt, ttl)))
+
+	if isNotModified(req, etag, lastModified) {
+		res.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	res.WriteHeader(http.StatusOK)
+
+	// HEAD replies carry the headers of the GET ones without the body
+	if req.Method != http.MethodHead {
+		res.Write(body.Bytes())
+	}
+}
@@ -1,23 +1,20 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ceticamarco/zephyr/model"
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func jsonError(res http.ResponseWriter, key string, value string, status int) {
-	res.Header().Set("Content-Type", "application/json")
-	res.WriteHeader(status)
-	json.NewEncoder(res).Encode(map[string]string{key: value})
-}
-
 func jsonValue(res http.ResponseWriter, val any) {
 	res.Header().Set("Content-Type", "application/json")
 	res.WriteHeader(http.StatusOK)
@@ -58,9 +55,30 @@ func fmtWind(windSpeed string, isImperial bool) string {
 }
 
 func fmtKey(key string) string {
-	// Format cache/database keys by replacing whitespaces with '+' token
-	// and making them uppercase
-	return strings.ToUpper(strings.ReplaceAll(key, " ", "+"))
+	// Format cache/database keys by removing whitespaces around commas,
+	// replacing the remaining ones with '+' token and making them uppercase
+	parts := strings.Split(key, ",")
+	for idx, part := range parts {
+		parts[idx] = strings.Join(strings.Fields(part), "+")
+	}
+
+	return strings.ToUpper(strings.Join(parts, ","))
+}
+
+func locationKey(city types.City) string {
+	// Format cache/database keys from the coordinates of a location
+	// rounded to two decimal places(~1km), so that every alias of
+	// the same city shares the same entry
+	round := func(coord float64) float64 {
+		rounded := math.Round(coord*100) / 100
+		if rounded == 0 {
+			return 0 // Avoid negative zero
+		}
+
+		return rounded
+	}
+
+	return fmt.Sprintf("%.2f,%.2f", round(city.Lat), round(city.Lon))
 }
 
 func deepCopyForecast(original types.Forecast) types.Forecast {
@@ -76,256 +94,276 @@ func deepCopyForecast(original types.Forecast) types.Forecast {
 	return fc_copy
 }
 
-func GetWeather(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Weather], statDB *types.StatDB, vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
-		return
+// getData returns the cached value of a key when it is still valid, otherwise
+// it retrieves a new value from the upstream provider and caches it,
+// sharing a single upstream call among concurrent requests. The call runs
+// on the context passed to fetch, which outlives the requests waiting for it.
+// Expired values are served instead when the daily quota is running low
+// or when the upstream provider cannot be reached. The returned
+// time reports when the value has been fetched
+func getData[T types.CacheType](ctx context.Context, cache *types.Cache[T], key string, ttl time.Duration, fetch func(ctx context.Context) (T, error)) (T, time.Time, error) {
+	requestLog := types.GetRequestLog(ctx)
+
+	cachedValue, fetchedAt, found := cache.GetTimedEntry(key, ttl)
+	if found {
+		requestLog.SetCache("hit")
+		return cachedValue, fetchedAt, nil
 	}
 
-	// Extract city name from '/weather/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/weather/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
-
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	staleValue, staleFetchedAt, isStale := cache.GetStaleEntry(key, ttl+types.StaleWindow)
+	if isStale && model.Upstream.IsThrottled() {
+		requestLog.SetCache("stale")
+		return staleValue, staleFetchedAt, nil
+	}
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format weather object and then return it
-		cachedValue.Temperature = fmtTemperature(cachedValue.Temperature, isImperial)
-		cachedValue.FeelsLike = fmtTemperature(cachedValue.FeelsLike, isImperial)
-
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
+	requestLog.SetCache("miss")
+	value, fetchedAt, err := cache.Coalesce(ctx, key, fetch)
+	if err != nil {
+		var upstreamErr *model.UpstreamError
+		if isStale && errors.As(err, &upstreamErr) {
+			requestLog.SetCache("stale")
+			return staleValue, staleFetchedAt, nil
 		}
 
-		// Get city weather
-		weather, err := model.GetWeather(&city, vars.Token)
+		return value, time.Time{}, err
+	}
+
+	return value, fetchedAt, nil
+}
+
+// fetchWeather returns the weather of a location, recording
+// each new observation into the statistical database
+func fetchWeather(ctx context.Context, city types.City, cache *types.Cache[types.Weather], statDB *types.StatDB, vars *types.Variables) (types.Weather, time.Time, error) {
+	key := locationKey(city)
+
+	return getData(ctx, cache, key, vars.TimeToLive.Weather, func(ctx context.Context) (types.Weather, error) {
+		weather, err := model.GetWeather(ctx, &city, vars.Token)
 		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
+			return weather, err
 		}
 
-		// Add result to cache
-		cache.AddEntry(weather, fmtKey(cityName))
-
 		// Insert new statistic entry into the statistics database
-		statDB.AddStatistic(fmtKey(cityName), weather)
+		statDB.AddStatistic(key, weather)
 
-		// Format weather object and then return it
-		weather.Temperature = fmtTemperature(weather.Temperature, isImperial)
-		weather.FeelsLike = fmtTemperature(weather.FeelsLike, isImperial)
+		return weather, nil
+	})
+}
 
-		jsonValue(res, weather)
+// RefreshWeather fetches the weather of a watched city(e.g. 'Milan,IT'),
+// so that its statistics are available without waiting for clients
+func RefreshWeather(ctx context.Context, cityName string, cache *types.Cache[types.Weather], geoCache *types.Cache[types.Candidates], statDB *types.StatDB, vars *types.Variables) error {
+	city, err := getCity(ctx, cityName, "", geoCache, vars)
+	if err != nil {
+		return err
 	}
+
+	_, _, err = fetchWeather(ctx, city, cache, statDB, vars)
+
+	return err
 }
 
-func GetMetrics(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Metrics], vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+func GetWeather(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Weather], geoCache *types.Cache[types.Candidates], statDB *types.StatDB, vars *types.Variables) {
+	// Extract city name from '/weather/:city'
+	cityName, err := cityParam(req)
+	if err != nil {
+		jsonProblem(res, req, err)
 		return
 	}
 
-	// Extract city name from '/metrics/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/metrics/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
-
 	// Check whether the 'i' parameter(imperial mode) is specified
 	isImperial := req.URL.Query().Has("i")
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format metrics object and then return it
-		cachedValue.Humidity = fmt.Sprintf("%s%%", cachedValue.Humidity)
-		cachedValue.Pressure = fmt.Sprintf("%s hPa", cachedValue.Pressure)
-		cachedValue.DewPoint = fmtTemperature(cachedValue.DewPoint, isImperial)
-		cachedValue.Visibility = fmt.Sprintf("%skm", cachedValue.Visibility)
-
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		// Get city weather
-		metrics, err := model.GetMetrics(&city, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	// Resolve the requested location
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-		// Add result to cache
-		cache.AddEntry(metrics, fmtKey(cityName))
+	// Get city weather
+	weather, fetchedAt, err := fetchWeather(req.Context(), city, cache, statDB, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-		// Format metrics object and then return it
-		metrics.Humidity = fmt.Sprintf("%s%%", metrics.Humidity)
-		metrics.Pressure = fmt.Sprintf("%s hPa", metrics.Pressure)
-		metrics.DewPoint = fmtTemperature(metrics.DewPoint, isImperial)
-		metrics.Visibility = fmt.Sprintf("%skm", metrics.Visibility)
+	// Format weather object and then return it
+	weather.Temperature = fmtTemperature(weather.Temperature, isImperial)
+	weather.FeelsLike = fmtTemperature(weather.FeelsLike, isImperial)
 
-		jsonValue(res, metrics)
+	// Return the resolved location when queried by coordinates or postal code
+	if isResolved {
+		weather.Location = &city
 	}
+
+	jsonCached(res, req, weather, fetchedAt, vars.TimeToLive.Weather)
 }
 
-func GetWind(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Wind], vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+func GetMetrics(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Metrics], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	// Extract city name from '/metrics/:city'
+	cityName, err := cityParam(req)
+	if err != nil {
+		jsonProblem(res, req, err)
 		return
 	}
 
-	// Extract city name from '/wind/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/wind/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
-
 	// Check whether the 'i' parameter(imperial mode) is specified
 	isImperial := req.URL.Query().Has("i")
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format wind object and then return it
-		cachedValue.Speed = fmtWind(cachedValue.Speed, isImperial)
-
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		// Get city wind
-		wind, err := model.GetWind(&city, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	// Resolve the requested location
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-		// Add result to cache
-		cache.AddEntry(wind, fmtKey(cityName))
+	// Get city metrics
+	metrics, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Metrics, func(ctx context.Context) (types.Metrics, error) {
+		return model.GetMetrics(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-		// Format wind object and then return it
-		wind.Speed = fmtWind(wind.Speed, isImperial)
+	// Format metrics object and then return it
+	metrics.Humidity = fmt.Sprintf("%s%%", metrics.Humidity)
+	metrics.Pressure = fmt.Sprintf("%s hPa", metrics.Pressure)
+	metrics.DewPoint = fmtTemperature(metrics.DewPoint, isImperial)
+	metrics.Visibility = fmt.Sprintf("%skm", metrics.Visibility)
 
-		jsonValue(res, wind)
+	// Return the resolved location when queried by coordinates or postal code
+	if isResolved {
+		metrics.Location = &city
 	}
+
+	jsonCached(res, req, metrics, fetchedAt, vars.TimeToLive.Metrics)
 }
 
-func GetForecast(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Forecast], vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+func GetWind(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Wind], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	// Extract city name from '/wind/:city'
+	cityName, err := cityParam(req)
+	if err != nil {
+		jsonProblem(res, req, err)
 		return
 	}
 
-	// Extract city name from '/forecast/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/forecast/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
-
 	// Check whether the 'i' parameter(imperial mode) is specified
 	isImperial := req.URL.Query().Has("i")
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		forecast := deepCopyForecast(cachedValue)
-
-		// Format forecast object and then return it
-		for idx := range forecast.Forecast {
-			val := &forecast.Forecast[idx]
+	// Resolve the requested location
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-			val.Min = fmtTemperature(val.Min, isImperial)
-			val.Max = fmtTemperature(val.Max, isImperial)
-			val.FeelsLike = fmtTemperature(val.FeelsLike, isImperial)
-			val.Wind.Speed = fmtWind(val.Wind.Speed, isImperial)
-		}
+	// Get city wind
+	wind, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Wind, func(ctx context.Context) (types.Wind, error) {
+		return model.GetWind(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-		jsonValue(res, forecast)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	// Format wind object and then return it
+	wind.Speed = fmtWind(wind.Speed, isImperial)
 
-		// Get city forecast
-		forecast, err := model.GetForecast(&city, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	// Return the resolved location when queried by coordinates or postal code
+	if isResolved {
+		wind.Location = &city
+	}
 
-		// Add result to cache
-		cache.AddEntry(deepCopyForecast(forecast), fmtKey(cityName))
+	jsonCached(res, req, wind, fetchedAt, vars.TimeToLive.Wind)
+}
 
-		// Format forecast object and then return it
-		for idx := range forecast.Forecast {
-			val := &forecast.Forecast[idx]
+func GetForecast(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Forecast], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	// Extract city name from '/forecast/:city'
+	cityName, err := cityParam(req)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
-			val.Min = fmtTemperature(val.Min, isImperial)
-			val.Max = fmtTemperature(val.Max, isImperial)
-			val.FeelsLike = fmtTemperature(val.FeelsLike, isImperial)
-			val.Wind.Speed = fmtWind(val.Wind.Speed, isImperial)
-		}
+	// Check whether the 'i' parameter(imperial mode) is specified
+	isImperial := req.URL.Query().Has("i")
 
-		jsonValue(res, forecast)
+	// Resolve the requested location
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
 	}
-}
 
-func GetMoon(res http.ResponseWriter, req *http.Request, cache *types.CacheEntity[types.Moon], vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+	// Get city forecast
+	cachedValue, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Forecast, func(ctx context.Context) (types.Forecast, error) {
+		return model.GetForecast(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
 		return
 	}
 
-	cachedValue, found := cache.GetEntry(vars.TimeToLive)
-	if found {
-		// Format moon object and then return it
-		cachedValue.Percentage = fmt.Sprintf("%s%%", cachedValue.Percentage)
+	// Copy the forecast to avoid altering the cached value
+	forecast := deepCopyForecast(cachedValue)
 
-		jsonValue(res, cachedValue)
-	} else {
-		// Get moon data
-		moon, err := model.GetMoon(vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		// Add result to cache
-		cache.AddEntry(moon)
+	// Format forecast object and then return it
+	for idx := range forecast.Forecast {
+		val := &forecast.Forecast[idx]
 
-		// Format moon object and then return it
-		moon.Percentage = fmt.Sprintf("%s%%", moon.Percentage)
+		val.Min = fmtTemperature(val.Min, isImperial)
+		val.Max = fmtTemperature(val.Max, isImperial)
+		val.FeelsLike = fmtTemperature(val.FeelsLike, isImperial)
+		val.Wind.Speed = fmtWind(val.Wind.Speed, isImperial)
+	}
 
-		jsonValue(res, moon)
+	// Return the resolved location when queried by coordinates or postal code
+	if isResolved {
+		forecast.Location = &city
 	}
+
+	jsonCached(res, req, forecast, fetchedAt, vars.TimeToLive.Forecast)
 }
 
-func GetStatistics(res http.ResponseWriter, req *http.Request, statDB *types.StatDB) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+func GetMoon(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Moon], vars *types.Variables) {
+	// Get moon data
+	moon, fetchedAt, err := getData(req.Context(), cache, types.MoonKey, vars.TimeToLive.Moon, func(ctx context.Context) (types.Moon, error) {
+		return model.GetMoon(ctx, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
 		return
 	}
 
+	// Format moon object and then return it
+	moon.Percentage = fmt.Sprintf("%s%%", moon.Percentage)
+
+	jsonCached(res, req, moon, fetchedAt, vars.TimeToLive.Moon)
+}
+
+func GetStatistics(res http.ResponseWriter, req *http.Request, statDB *types.StatDB, geoCache *types.Cache[types.Candidates], vars *types.Variables) {
 	// Extract city name from '/stats/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/stats/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+	cityName, err := cityParam(req)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
 
 	// Check whether the 'i' parameter(imperial mode) is specified
 	isImperial := req.URL.Query().Has("i")
 
+	// Resolve the requested location
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
 	// Get city statistics
-	stats, err := model.GetStatistics(fmtKey(cityName), statDB)
+	stats, err := model.GetStatistics(locationKey(city), statDB)
 	if err != nil {
-		jsonError(res, "error", err.Error(), http.StatusBadRequest)
+		jsonProblem(res, req, err)
 		return
 	}
 
@@ -342,5 +380,10 @@ func GetStatistics(res http.ResponseWriter, req *http.Request, statDB *types.Sta
 		}
 	}
 
+	// Return the resolved location when queried by coordinates or postal code
+	if isResolved {
+		stats.Location = &city
+	}
+
 	jsonValue(res, stats)
 }
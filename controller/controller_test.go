@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+type TestEntry struct {
+	Name     string
+	Input    string
+	Expected string
+}
+
+func TestFmtKey(t *testing.T) {
+	tests := []TestEntry{
+		{"Single word", "milan", "MILAN"},
+		{"Multiple words", "new  york", "NEW+YORK"},
+		{"Country code", "Milan , IT", "MILAN,IT"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := fmtKey(test.Input)
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestLocationKey(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    types.City
+		Expected string
+	}{
+		{"Rounded coordinates", types.City{Lat: 45.4641943, Lon: 9.1896346}, "45.46,9.19"},
+		{"Negative zero", types.City{Lat: -0.001, Lon: -73.9866}, "0.00,-73.99"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := locationKey(test.Input)
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestGetDataStale(t *testing.T) {
+	cache := types.InitCache()
+	cache.WindCache.AddEntry(types.Wind{Direction: "N"}, "45.46,9.19")
+
+	tests := []struct {
+		Name     string
+		Err      error
+		Expected string
+		IsValid  bool
+	}{
+		{"Upstream failure", &model.UpstreamError{Err: model.ErrUpstreamDown}, "N", true},
+		{"Other failures", model.ErrCityNotFound, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			// The entry is expired, but it can still be served as stale data
+			got, _, err := getData(context.Background(), &cache.WindCache, "45.46,9.19", time.Nanosecond, func(ctx context.Context) (types.Wind, error) {
+				return types.Wind{}, test.Err
+			})
+
+			if (err == nil) != test.IsValid || got.Direction != test.Expected {
+				t.Errorf("Got (%+v, %v), wanted %s", got, err, test.Expected)
+			}
+		})
+	}
+}
+
+func TestJsonCached(t *testing.T) {
+	fetchedAt := time.Now().Add(-10 * time.Minute)
+	wind := types.Wind{Direction: "N", Speed: "5.0 km/h"}
+
+	res := httptest.NewRecorder()
+	jsonCached(res, httptest.NewRequest(http.MethodGet, "/wind/milan", nil), wind, fetchedAt, 30*time.Minute)
+
+	etag := res.Header().Get("ETag")
+	if res.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Got (%d, %q), wanted a 200 response with an ETag", res.Code, etag)
+	}
+
+	if got := res.Header().Get("Cache-Control"); got != "public, max-age=1199" && got != "public, max-age=1200" {
+		t.Errorf("Got %s, wanted about 20 minutes", got)
+	}
+
+	tests := []struct {
+		Name     string
+		Header   string
+		Value    string
+		Expected int
+	}{
+		{"Matching ETag", "If-None-Match", etag, http.StatusNotModified},
+		{"Different ETag", "If-None-Match", `"outdated"`, http.StatusOK},
+		{"Not modified since", "If-Modified-Since", time.Now().UTC().Format(http.TimeFormat), http.StatusNotModified},
+		{"Modified since", "If-Modified-Since", fetchedAt.Add(-time.Hour).UTC().Format(http.TimeFormat), http.StatusOK},
+		{"Weak ETag in a list", "If-None-Match", `"outdated", W/` + etag, http.StatusNotModified},
+		{"Range", "Range", "bytes=0-10", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/wind/milan", nil)
+			req.Header.Set(test.Header, test.Value)
+
+			res := httptest.NewRecorder()
+			jsonCached(res, req, wind, fetchedAt, 30*time.Minute)
+
+			if res.Code != test.Expected {
+				t.Errorf("Got %d, wanted %d", res.Code, test.Expected)
+			}
+
+			if test.Expected == http.StatusOK && !json.Valid(res.Body.Bytes()) {
+				t.Errorf("Got %q, wanted the whole JSON document", res.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// APIError, representing an error returned to clients along with
+// a stable, machine-readable error code
+type APIError struct {
+	Status     int
+	Code       string
+	Title      string
+	Detail     string
+	Extensions map[string]any
+}
+
+func (err *APIError) Error() string {
+	return err.Detail
+}
+
+var (
+	errUnauthorized = &APIError{
+		Status: http.StatusUnauthorized,
+		Code:   "unauthorized",
+		Title:  "Unauthorized",
+		Detail: "Missing or invalid credentials",
+	}
+)
+
+func badRequest(detail string) *APIError {
+	return &APIError{
+		Status: http.StatusBadRequest,
+		Code:   "invalid-request",
+		Title:  "Invalid request",
+		Detail: detail,
+	}
+}
+
+func notFound(code string, detail string) *APIError {
+	return &APIError{
+		Status: http.StatusNotFound,
+		Code:   code,
+		Title:  "Not found",
+		Detail: detail,
+	}
+}
+
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var ambiguousErr *ambiguousCityError
+	if errors.As(err, &ambiguousErr) {
+		return &APIError{
+			Status:     http.StatusMultipleChoices,
+			Code:       "ambiguous-city",
+			Title:      "Ambiguous city name",
+			Detail:     ambiguousErr.Error(),
+			Extensions: map[string]any{"candidates": ambiguousErr.candidates},
+		}
+	}
+
+	// Map model errors to their HTTP status. Upstream errors do not
+	// expose their details since they may leak internal information
+	errorMap := []struct {
+		target error
+		status int
+		code   string
+		title  string
+	}{
+		{model.ErrCityNotFound, http.StatusNotFound, "city-not-found", "Not found"},
+		{model.ErrZipNotFound, http.StatusNotFound, "postal-code-not-found", "Not found"},
+		{model.ErrInsufficientData, http.StatusUnprocessableEntity, "insufficient-data", "Insufficient data"},
+		{model.ErrCircuitOpen, http.StatusServiceUnavailable, "upstream-circuit-open", "Upstream unavailable"},
+		{model.ErrQuotaExhausted, http.StatusServiceUnavailable, "quota-exhausted", "Upstream quota exhausted"},
+		{model.ErrRateLimited, http.StatusTooManyRequests, "upstream-rate-limited", "Upstream quota exceeded"},
+		{model.ErrTimeout, http.StatusGatewayTimeout, "upstream-timeout", "Upstream timeout"},
+		{model.ErrUpstreamDown, http.StatusServiceUnavailable, "upstream-unavailable", "Upstream unavailable"},
+		{model.ErrUnauthorized, http.StatusBadGateway, "upstream-unauthorized", "Bad gateway"},
+		{model.ErrNotFound, http.StatusBadGateway, "upstream-not-found", "Bad gateway"},
+		{model.ErrBadRequest, http.StatusBadGateway, "upstream-bad-request", "Bad gateway"},
+		{model.ErrInvalidResponse, http.StatusBadGateway, "upstream-invalid-response", "Bad gateway"},
+	}
+
+	for _, val := range errorMap {
+		if errors.Is(err, val.target) {
+			return &APIError{
+				Status: val.status,
+				Code:   val.code,
+				Title:  val.title,
+				Detail: val.target.Error(),
+			}
+		}
+	}
+
+	return &APIError{
+		Status: http.StatusInternalServerError,
+		Code:   "internal-error",
+		Title:  "Internal server error",
+		Detail: "An unexpected error occurred",
+	}
+}
+
+func jsonProblem(res http.ResponseWriter, req *http.Request, err error) {
+	apiErr := toAPIError(err)
+	if isV2(req) {
+		jsonErrorV2(res, req, apiErr)
+		return
+	}
+
+	// Build an RFC 7807 problem details object. Standard members
+	// take precedence over extension members
+	problem := make(map[string]any, len(apiErr.Extensions)+7)
+	for key, val := range apiErr.Extensions {
+		problem[key] = val
+	}
+
+	problem["type"] = "urn:zephyr:error:" + apiErr.Code
+	problem["title"] = apiErr.Title
+	problem["status"] = apiErr.Status
+	problem["detail"] = apiErr.Detail
+	problem["instance"] = req.URL.Path
+	problem["code"] = apiErr.Code
+	if requestID := types.RequestID(req.Context()); requestID != "" {
+		problem["requestId"] = requestID
+	}
+
+	res.Header().Set("Content-Type", "application/problem+json")
+	res.WriteHeader(apiErr.Status)
+	json.NewEncoder(res).Encode(problem)
+}
+
+// WriteProblem allows middlewares to report errors
+// using the same format of the controllers
+func WriteProblem(res http.ResponseWriter, req *http.Request, err error) {
+	jsonProblem(res, req, err)
+}
+
+// isV2 reports whether a request targets the '/v2/' API
+func isV2(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.Path, "/v2/")
+}
+
+// jsonErrorV2 reports an error of the '/v2/' API, which wraps the same
+// members of the problem details into an 'error' envelope
+func jsonErrorV2(res http.ResponseWriter, req *http.Request, apiErr *APIError) {
+	detail := make(map[string]any, len(apiErr.Extensions)+5)
+	for key, val := range apiErr.Extensions {
+		detail[key] = val
+	}
+
+	detail["code"] = apiErr.Code
+	detail["status"] = apiErr.Status
+	detail["title"] = apiErr.Title
+	detail["detail"] = apiErr.Detail
+	if requestID := types.RequestID(req.Context()); requestID != "" {
+		detail["requestId"] = requestID
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(apiErr.Status)
+	json.NewEncoder(res).Encode(map[string]any{"error": detail})
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestToAPIError(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    error
+		Expected int
+	}{
+		{"Invalid request", badRequest("Missing city name"), http.StatusBadRequest},
+		{"Unknown city", model.ErrCityNotFound, http.StatusNotFound},
+		{"Insufficient statistics", model.ErrInsufficientData, http.StatusUnprocessableEntity},
+		{"Upstream rate limit", &model.UpstreamError{Err: model.ErrRateLimited}, http.StatusTooManyRequests},
+		{"Upstream down", &model.UpstreamError{Err: model.ErrUpstreamDown}, http.StatusServiceUnavailable},
+		{"Upstream timeout", &model.UpstreamError{Err: model.ErrTimeout}, http.StatusGatewayTimeout},
+		{"Upstream invalid response", fmt.Errorf("wrapped: %w", model.ErrInvalidResponse), http.StatusBadGateway},
+		{"Unknown error", fmt.Errorf("unexpected"), http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := toAPIError(test.Input).Status
+
+			if got != test.Expected {
+				t.Errorf("Got %d, wanted %d", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestJsonProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/stats/milan", nil)
+	req = req.WithContext(types.WithRequestID(req.Context(), "abc123"))
+	res := httptest.NewRecorder()
+
+	jsonProblem(res, req, model.ErrInsufficientData)
+
+	if got := res.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Got %s, wanted application/problem+json", got)
+	}
+
+	var problem map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&problem); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if problem["code"] != "insufficient-data" || problem["requestId"] != "abc123" || problem["instance"] != "/stats/milan" {
+		t.Errorf("Got %v", problem)
+	}
+}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Error representing a query matching several distinct locations
+type ambiguousCityError struct {
+	candidates types.Candidates
+}
+
+func (err *ambiguousCityError) Error() string {
+	return "Ambiguous city name, please specify a state and/or a country code"
+}
+
+// Longest accepted query(e.g. 'city,state,country')
+const maxQueryLength = 128
+
+func isCountryCode(code string) bool {
+	// Country codes must follow the ISO 3166-1 alpha-2 format
+	if len(code) != 2 {
+		return false
+	}
+
+	for _, ch := range code {
+		if (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseQuery(cityName string, country string) (string, bool, error) {
+	if len(cityName) > maxQueryLength {
+		return "", false, badRequest(fmt.Sprintf("City name too long(at most %d characters)", maxQueryLength))
+	}
+
+	// Split queries in the form of 'city,state,country'
+	parts := strings.Split(cityName, ",")
+	for idx, part := range parts {
+		parts[idx] = strings.TrimSpace(part)
+	}
+
+	if parts[0] == "" {
+		return "", false, badRequest("Missing city name")
+	}
+
+	if len(parts) > 3 {
+		return "", false, badRequest("Queries must be in the form of 'city,state,country'")
+	}
+
+	// Append the country code of the 'country' parameter, if specified
+	if country != "" {
+		if len(parts) == 3 {
+			return "", false, badRequest("Country code specified twice")
+		}
+
+		parts = append(parts, country)
+	}
+
+	// When the query is qualified, its last element must be a country code
+	isQualified := len(parts) > 1
+	if isQualified && !isCountryCode(parts[len(parts)-1]) {
+		return "", false, badRequest("Invalid country code, expected an ISO 3166 code(e.g. 'IT')")
+	}
+
+	return strings.Join(parts, ","), isQualified, nil
+}
+
+// cityParam extracts the city name from the path of a request, refusing
+// invalid names before reaching the geocoding service. The name may be
+// omitted when the location is given through coordinates or postal code
+func cityParam(req *http.Request) (string, error) {
+	cityName := strings.TrimSpace(strings.Trim(req.PathValue("city"), "/"))
+	params := req.URL.Query()
+
+	switch {
+	case cityName == "" && !params.Has("lat") && !params.Has("lon") && !params.Has("zip"):
+		return "", badRequest("Missing city name, coordinates or postal code")
+	case len(cityName) > maxQueryLength:
+		return "", badRequest(fmt.Sprintf("City name too long(at most %d characters)", maxQueryLength))
+	case strings.ContainsRune(cityName, '/') || strings.IndexFunc(cityName, unicode.IsControl) != -1:
+		return "", badRequest("Invalid city name")
+	}
+
+	return cityName, nil
+}
+
+func isAmbiguous(candidates types.Candidates) bool {
+	// The geocoding service may return several entries for the same place,
+	// thus a query is ambiguous only if it matches distinct countries or states
+	for _, candidate := range candidates[1:] {
+		if candidate.Country != candidates[0].Country || candidate.State != candidates[0].State {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getCandidates(ctx context.Context, query string, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.Candidates, error) {
+	// City coordinates never change, thus we query the geocoding
+	// service only if they are not cached yet
+	cachedCandidates, found := geoCache.GetEntry(fmtKey(query), vars.TimeToLive.Geocoding)
+	if found {
+		return cachedCandidates, nil
+	}
+
+	candidates, err := model.GetCandidates(ctx, query, vars.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add result to cache
+	geoCache.AddEntry(candidates, fmtKey(query))
+
+	return candidates, nil
+}
+
+func getCity(ctx context.Context, cityName string, country string, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.City, error) {
+	query, isQualified, err := parseQuery(cityName, country)
+	if err != nil {
+		return types.City{}, err
+	}
+
+	candidates, err := getCandidates(ctx, query, geoCache, vars)
+	if err != nil {
+		return types.City{}, err
+	}
+
+	// On strict mode, refuse to guess the location of unqualified
+	// queries matching several places
+	if vars.StrictGeocoding && !isQualified && isAmbiguous(candidates) {
+		return types.City{}, &ambiguousCityError{candidates: candidates}
+	}
+
+	// Otherwise pick the most relevant location
+	return candidates[0], nil
+}
+
+func parseCoordinates(latValue string, lonValue string) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(latValue, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, badRequest("Invalid latitude, expected a value between -90 and 90")
+	}
+
+	lon, err := strconv.ParseFloat(lonValue, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, badRequest("Invalid longitude, expected a value between -180 and 180")
+	}
+
+	return lat, lon, nil
+}
+
+// getLocation resolves the location of a request, recording it into the access log
+func getLocation(req *http.Request, cityName string, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.City, bool, error) {
+	city, isResolved, err := resolveLocation(req.Context(), req.URL.Query(), cityName, geoCache, vars)
+	if err == nil {
+		types.GetRequestLog(req.Context()).SetCity(fmt.Sprintf("%s,%s", city.Name, city.Country))
+	}
+
+	return city, isResolved, err
+}
+
+// resolveLocation resolves a location from either the city name or the
+// 'lat', 'lon' and 'zip' parameters, reporting whether it has been resolved
+// from coordinates or postal code
+func resolveLocation(ctx context.Context, params url.Values, cityName string, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.City, bool, error) {
+	// Resolve the location from the 'lat' and 'lon' parameters
+	if params.Has("lat") || params.Has("lon") {
+		lat, lon, err := parseCoordinates(params.Get("lat"), params.Get("lon"))
+		if err != nil {
+			return types.City{}, false, err
+		}
+
+		key := "COORDS:" + locationKey(types.City{Lat: lat, Lon: lon})
+		cachedLocation, found := geoCache.GetEntry(key, vars.TimeToLive.Geocoding)
+		if found {
+			location := cachedLocation[0]
+			location.Lat, location.Lon = lat, lon
+
+			return location, true, nil
+		}
+
+		location, err := model.GetLocationByCoords(ctx, lat, lon, vars.Token)
+		if err != nil {
+			return types.City{}, false, err
+		}
+
+		// Add result to cache
+		geoCache.AddEntry(types.Candidates{location}, key)
+
+		return location, true, nil
+	}
+
+	// Resolve the location from the 'zip' parameter(e.g. 'zip=10001,US')
+	if params.Has("zip") {
+		zipCode, country, found := strings.Cut(params.Get("zip"), ",")
+		zipCode, country = strings.TrimSpace(zipCode), strings.TrimSpace(country)
+		if !found || zipCode == "" || !isCountryCode(country) {
+			return types.City{}, false, badRequest("Invalid postal code, expected a value in the form of 'CODE,COUNTRY'")
+		}
+
+		key := "ZIP:" + fmtKey(zipCode+","+country)
+		cachedLocation, found := geoCache.GetEntry(key, vars.TimeToLive.Geocoding)
+		if found {
+			return cachedLocation[0], true, nil
+		}
+
+		location, err := model.GetLocationByZip(ctx, zipCode+","+country, vars.Token)
+		if err != nil {
+			return types.City{}, false, err
+		}
+
+		// Add result to cache
+		geoCache.AddEntry(types.Candidates{location}, key)
+
+		return location, true, nil
+	}
+
+	// Otherwise resolve the location from the city name
+	city, err := getCity(ctx, cityName, params.Get("country"), geoCache, vars)
+
+	return city, false, err
+}
+
+func GetGeocode(res http.ResponseWriter, req *http.Request, geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	// Extract query from '/geocode?q=city,state,country'
+	query, _, err := parseQuery(req.URL.Query().Get("q"), req.URL.Query().Get("country"))
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	// Get every location matching the query
+	candidates, err := getCandidates(req.Context(), query, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	jsonValue(res, map[string]types.Candidates{"candidates": candidates})
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		Name      string
+		City      string
+		Country   string
+		Expected  string
+		Qualified bool
+		IsValid   bool
+	}{
+		{"City only", "paris", "", "paris", false, true},
+		{"City and country", "paris, fr", "", "paris,fr", true, true},
+		{"City, state and country", "springfield,il,us", "", "springfield,il,us", true, true},
+		{"Country parameter", "springfield,il", "us", "springfield,il,us", true, true},
+		{"Invalid country code", "paris,france", "", "", false, false},
+		{"Country code specified twice", "springfield,il,us", "us", "", false, false},
+		{"Missing city name", "", "", "", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, isQualified, err := parseQuery(test.City, test.Country)
+
+			if (err == nil) != test.IsValid {
+				t.Fatalf("Got error %v, wanted valid=%v", err, test.IsValid)
+			}
+
+			if got != test.Expected || isQualified != test.Qualified {
+				t.Errorf("Got (%s, %v), wanted (%s, %v)", got, isQualified, test.Expected, test.Qualified)
+			}
+		})
+	}
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Candidates types.Candidates
+		Expected   bool
+	}{
+		{"Single location", types.Candidates{{Name: "Paris", Country: "FR"}}, false},
+		{"Same location", types.Candidates{{Name: "Paris", Country: "FR"}, {Name: "Paris", Country: "FR"}}, false},
+		{"Distinct countries", types.Candidates{{Name: "Paris", Country: "FR"}, {Name: "Paris", Country: "US", State: "Texas"}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := isAmbiguous(test.Candidates)
+
+			if got != test.Expected {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Lat     string
+		Lon     string
+		IsValid bool
+	}{
+		{"Valid coordinates", "45.4642", "9.19", true},
+		{"Latitude out of range", "91", "9.19", false},
+		{"Longitude out of range", "45.4642", "-181", false},
+		{"Missing longitude", "45.4642", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, _, err := parseCoordinates(test.Lat, test.Lon)
+
+			if (err == nil) != test.IsValid {
+				t.Errorf("Got error %v, wanted valid=%v", err, test.IsValid)
+			}
+		})
+	}
+}
+
+func TestCityParam(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Path     string
+		City     string
+		Expected string
+		IsValid  bool
+	}{
+		{"City name", "/weather/milan", "milan", "milan", true},
+		{"Trailing slash", "/weather/milan/", "milan/", "milan", true},
+		{"Missing city name", "/weather/", "", "", false},
+		{"Coordinates", "/weather/?lat=45.4642&lon=9.19", "", "", true},
+		{"Postal code", "/weather/?zip=10001,US", "", "", true},
+		{"Too long", "/weather/x", strings.Repeat("x", maxQueryLength+1), "", false},
+		{"Nested path", "/weather/milan/it", "milan/it", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			req.SetPathValue("city", test.City)
+
+			got, err := cityParam(req)
+			if (err == nil) != test.IsValid {
+				t.Fatalf("Got error %v, wanted valid=%v", err, test.IsValid)
+			}
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Largest accepted GraphQL request body
+const maxGraphQLSize = 64 << 10
+
+// Most cities resolved by a single query, since each
+// of them may require several upstream calls
+const maxGraphQLCities = 10
+
+// graphQLRequest, representing a GraphQL request sent over HTTP
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// graphQLError, carrying the error code of the HTTP API
+// into the 'extensions' member of GraphQL errors
+type graphQLError struct {
+	*APIError
+}
+
+func (err graphQLError) Extensions() map[string]any {
+	return map[string]any{"code": err.Code, "status": err.Status}
+}
+
+// graphQLState, representing the values shared by the resolvers of a request
+type graphQLState struct {
+	vars   *types.Variables
+	cities atomic.Int32
+}
+
+type graphQLStateKey struct{}
+
+func getGraphQLState(ctx context.Context) *graphQLState {
+	return ctx.Value(graphQLStateKey{}).(*graphQLState)
+}
+
+// cityNode, representing a city resolved by the 'city' field along
+// with the units requested for the data of its nested fields
+type cityNode struct {
+	city types.City
+	conv converter
+}
+
+// resolveCity wraps the resolver of a field nested into 'city',
+// converting the errors into GraphQL ones
+func resolveCity(resolve func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error)) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (any, error) {
+		val, err := resolve(params.Context, params.Source.(*cityNode), getGraphQLState(params.Context).vars, params.Args)
+		if err != nil {
+			return nil, graphQLError{toAPIError(err)}
+		}
+
+		return val, nil
+	}
+}
+
+// object describes a GraphQL object whose fields are
+// resolved from the JSON tags of the underlying type
+func object(name string, description string, fields graphql.Fields) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{Name: name, Description: description, Fields: fields})
+}
+
+var (
+	graphQLString   = graphql.NewNonNull(graphql.String)
+	graphQLFloat    = graphql.NewNonNull(graphql.Float)
+	graphQLInt      = graphql.NewNonNull(graphql.Int)
+	graphQLDateTime = graphql.NewNonNull(graphql.DateTime)
+)
+
+// NewGraphQLSchema returns the GraphQL schema of Zephyr, whose resolvers
+// share the caches and the statistical database of the HTTP endpoints
+func NewGraphQLSchema(cache *types.Caches, statDB *types.StatDB) (graphql.Schema, error) {
+	unitSystem := graphql.NewEnum(graphql.EnumConfig{
+		Name: "UnitSystem",
+		Values: graphql.EnumValueConfigMap{
+			"METRIC":   {Value: false},
+			"IMPERIAL": {Value: true},
+		},
+	})
+
+	units := object("Units", "Units of measurement of the values of a city", graphql.Fields{
+		"temperature": {Type: graphQLString},
+		"speed":       {Type: graphQLString},
+		"pressure":    {Type: graphQLString},
+		"distance":    {Type: graphQLString},
+	})
+
+	weather := object("Weather", "Current weather", graphql.Fields{
+		"date":        {Type: graphQLDateTime},
+		"temperature": {Type: graphQLFloat},
+		"condition":   {Type: graphQLString},
+		"feelsLike":   {Type: graphQLFloat},
+		"emoji":       {Type: graphQLString},
+	})
+
+	metrics := object("Metrics", "Current humidity(%), pressure, dew point, UV index and visibility", graphql.Fields{
+		"humidity":   {Type: graphQLInt},
+		"pressure":   {Type: graphQLInt},
+		"dewPoint":   {Type: graphQLFloat},
+		"uvIndex":    {Type: graphQLInt},
+		"visibility": {Type: graphQLFloat},
+	})
+
+	wind := object("Wind", "Wind at a certain time", graphql.Fields{
+		"arrow":     {Type: graphQLString},
+		"direction": {Type: graphQLString},
+		"speed":     {Type: graphQLFloat},
+	})
+
+	forecastDay := object("ForecastDay", "Weather forecast of a single day", graphql.Fields{
+		"date":      {Type: graphQLDateTime},
+		"min":       {Type: graphQLFloat},
+		"max":       {Type: graphQLFloat},
+		"condition": {Type: graphQLString},
+		"emoji":     {Type: graphQLString},
+		"feelsLike": {Type: graphQLFloat},
+		"wind":      {Type: graphql.NewNonNull(wind)},
+	})
+
+	moon := object("Moon", "Current moon phase and illumination(%)", graphql.Fields{
+		"icon":       {Type: graphQLString},
+		"phase":      {Type: graphQLString},
+		"percentage": {Type: graphQLInt},
+	})
+
+	sun := object("Sun", "Sunrise and sunset of a day, missing during the polar day and night", graphql.Fields{
+		"sunrise":   {Type: graphql.DateTime},
+		"sunset":    {Type: graphql.DateTime},
+		"dayLength": {Type: graphQLInt, Description: "Seconds of daylight"},
+	})
+
+	anomaly := object("Anomaly", "Skewed meteorological event", graphql.Fields{
+		"date":        {Type: graphQLDateTime},
+		"temperature": {Type: graphQLFloat},
+	})
+
+	stats := object("Statistics", "Statistics of the recorded temperatures", graphql.Fields{
+		"min":       {Type: graphQLFloat},
+		"max":       {Type: graphQLFloat},
+		"count":     {Type: graphQLInt},
+		"mean":      {Type: graphQLFloat},
+		"stdDev":    {Type: graphQLFloat},
+		"median":    {Type: graphQLFloat},
+		"mode":      {Type: graphQLFloat},
+		"anomalies": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(anomaly)))},
+	})
+
+	getMoon := func(ctx context.Context, vars *types.Variables) (types.MoonV2, error) {
+		moon, _, err := getData(ctx, &cache.MoonCache, types.MoonKey, vars.TimeToLive.Moon, func(ctx context.Context) (types.Moon, error) {
+			return model.GetMoon(ctx, vars.Token)
+		})
+
+		return toMoonV2(moon), err
+	}
+
+	// Each field of a city is only resolved when requested,
+	// hence a query fetches exactly the data it needs
+	city := object("City", "City along with its weather data", graphql.Fields{
+		"name":    {Type: graphQLString},
+		"country": {Type: graphQLString},
+		"state":   {Type: graphql.String},
+		"lat":     {Type: graphQLFloat},
+		"lon":     {Type: graphQLFloat},
+		"units": {
+			Type: graphql.NewNonNull(units),
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				return node.conv.units(), nil
+			}),
+		},
+		"weather": {
+			Type: weather,
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				weather, _, err := fetchWeather(ctx, node.city, &cache.WeatherCache, statDB, vars)
+
+				return node.conv.weather(weather), err
+			}),
+		},
+		"metrics": {
+			Type: metrics,
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				metrics, _, err := getData(ctx, &cache.MetricsCache, locationKey(node.city), vars.TimeToLive.Metrics, func(ctx context.Context) (types.Metrics, error) {
+					return model.GetMetrics(ctx, &node.city, vars.Token)
+				})
+
+				return node.conv.metrics(metrics), err
+			}),
+		},
+		"wind": {
+			Type: wind,
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				wind, _, err := getData(ctx, &cache.WindCache, locationKey(node.city), vars.TimeToLive.Wind, func(ctx context.Context) (types.Wind, error) {
+					return model.GetWind(ctx, &node.city, vars.Token)
+				})
+
+				return node.conv.wind(wind), err
+			}),
+		},
+		"forecast": {
+			Type: graphql.NewList(graphql.NewNonNull(forecastDay)),
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				forecast, _, err := getData(ctx, &cache.ForecastCache, locationKey(node.city), vars.TimeToLive.Forecast, func(ctx context.Context) (types.Forecast, error) {
+					return model.GetForecast(ctx, &node.city, vars.Token)
+				})
+
+				return node.conv.forecast(forecast).Forecast, err
+			}),
+		},
+		"moon": {
+			Type: moon,
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				return getMoon(ctx, vars)
+			}),
+		},
+		"sun": {
+			Type: graphql.NewNonNull(sun),
+			Args: graphql.FieldConfigArgument{
+				"date": {Type: graphql.String, Description: "Day formatted as 'YYYY-MM-DD', defaults to today"},
+			},
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				date := time.Now()
+				if value, isPresent := args["date"].(string); isPresent {
+					parsed, err := time.Parse(time.DateOnly, value)
+					if err != nil {
+						return nil, badRequest("Invalid date, expected a value in the form of 'YYYY-MM-DD'")
+					}
+					date = parsed
+				}
+
+				return model.GetSun(&node.city, date), nil
+			}),
+		},
+		"stats": {
+			Type: stats,
+			Resolve: resolveCity(func(ctx context.Context, node *cityNode, vars *types.Variables, args map[string]any) (any, error) {
+				stats, err := model.GetStatistics(locationKey(node.city), statDB)
+				if err != nil {
+					return nil, err
+				}
+
+				return node.conv.statistics(stats), nil
+			}),
+		},
+	})
+
+	query := object("Query", "", graphql.Fields{
+		"city": {
+			Type:        city,
+			Description: "City matching a name, optionally qualified as 'city,state,country'(e.g. 'Milan,IT')",
+			Args: graphql.FieldConfigArgument{
+				"name":    {Type: graphQLString},
+				"country": {Type: graphql.String, Description: "ISO 3166 country code of the city(e.g. 'IT')"},
+				"units":   {Type: unitSystem, DefaultValue: false},
+			},
+			Resolve: func(params graphql.ResolveParams) (any, error) {
+				state := getGraphQLState(params.Context)
+				if state.cities.Add(1) > maxGraphQLCities {
+					return nil, graphQLError{badRequest(fmt.Sprintf("Too many cities(at most %d per query)", maxGraphQLCities))}
+				}
+
+				name, _ := params.Args["name"].(string)
+				country, _ := params.Args["country"].(string)
+				isImperial, _ := params.Args["units"].(bool)
+
+				location, err := getCity(params.Context, name, country, &cache.GeoCache, state.vars)
+				if err != nil {
+					return nil, graphQLError{toAPIError(err)}
+				}
+
+				types.GetRequestLog(params.Context).SetCity(fmt.Sprintf("%s,%s", location.Name, location.Country))
+
+				return &cityNode{city: location, conv: converter{isImperial: isImperial}}, nil
+			},
+		},
+		"moon": {
+			Type: moon,
+			Resolve: func(params graphql.ResolveParams) (any, error) {
+				moon, err := getMoon(params.Context, getGraphQLState(params.Context).vars)
+				if err != nil {
+					return nil, graphQLError{toAPIError(err)}
+				}
+
+				return moon, nil
+			},
+		},
+	})
+
+	// The location of a city is resolved from its own fields
+	for name, resolve := range map[string]func(city types.City) any{
+		"name":    func(city types.City) any { return city.Name },
+		"country": func(city types.City) any { return city.Country },
+		"state":   func(city types.City) any { return city.State },
+		"lat":     func(city types.City) any { return city.Lat },
+		"lon":     func(city types.City) any { return city.Lon },
+	} {
+		city.Fields()[name].Resolve = func(params graphql.ResolveParams) (any, error) {
+			return resolve(params.Source.(*cityNode).city), nil
+		}
+	}
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// GetGraphQL executes a GraphQL query, sent either through the 'query'
+// parameter or as a JSON object in the body of a POST request
+func GetGraphQL(res http.ResponseWriter, req *http.Request, schema *graphql.Schema, vars *types.Variables) {
+	var gqlReq graphQLRequest
+
+	if req.Method == http.MethodPost {
+		decoder := json.NewDecoder(http.MaxBytesReader(res, req.Body, maxGraphQLSize))
+		if err := decoder.Decode(&gqlReq); err != nil {
+			jsonProblem(res, req, badRequest("Expected a JSON object with a 'query' field and optional 'variables' and 'operationName' fields"))
+			return
+		}
+	} else {
+		params := req.URL.Query()
+		gqlReq.Query = params.Get("query")
+		gqlReq.OperationName = params.Get("operationName")
+
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &gqlReq.Variables); err != nil {
+				jsonProblem(res, req, badRequest("Invalid variables, expected a JSON object"))
+				return
+			}
+		}
+	}
+
+	if strings.TrimSpace(gqlReq.Query) == "" {
+		jsonProblem(res, req, badRequest("Missing GraphQL query"))
+		return
+	}
+
+	// Errors are reported within the result, along with the resolved data
+	result := graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  gqlReq.Query,
+		VariableValues: gqlReq.Variables,
+		OperationName:  gqlReq.OperationName,
+		Context:        context.WithValue(req.Context(), graphQLStateKey{}, &graphQLState{vars: vars}),
+	})
+
+	jsonValue(res, result)
+}
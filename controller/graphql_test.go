@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGraphQL(t *testing.T) {
+	cache, vars := newTestCaches()
+	cache.GeoCache.AddEntry(types.Candidates{{Name: "Milan", Country: "IT", Lat: 45.46, Lon: 9.19}}, "MILAN,IT")
+
+	schema, err := NewGraphQLSchema(cache, types.InitDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(res http.ResponseWriter, req *http.Request) {
+		GetGraphQL(res, req, &schema, vars)
+	}
+
+	query := `{
+		city(name: "Milan", country: "IT", units: IMPERIAL) {
+			name
+			units { temperature speed }
+			weather { temperature condition }
+			wind { speed }
+			forecast { max }
+			sun(date: "2025-06-21") { dayLength }
+			stats { count }
+		}
+		moon { phase }
+	}`
+
+	// Fields are resolved independently, hence the missing
+	// statistics do not prevent the other fields from resolving
+	status, body := serve("/graphql?query="+url.QueryEscape(query), handler)
+	if status != http.StatusOK {
+		t.Fatalf("Got %d: %s", status, body)
+	}
+
+	var result struct {
+		Data struct {
+			City struct {
+				Name  string
+				Units struct {
+					Temperature string
+					Speed       string
+				}
+				Weather struct {
+					Temperature float64
+					Condition   string
+				}
+				Wind struct {
+					Speed float64
+				}
+				Forecast []struct {
+					Max float64
+				}
+				Sun struct {
+					DayLength int
+				}
+				Stats *struct{}
+			}
+			Moon struct {
+				Phase string
+			}
+		}
+		Errors []struct {
+			Path       []string
+			Extensions map[string]any
+		}
+	}
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	city := result.Data.City
+	if city.Name != "Milan" || city.Units.Temperature != "°F" || city.Units.Speed != "mph" ||
+		city.Weather.Temperature != 91.6 || city.Weather.Condition != "Clear" || city.Wind.Speed != 10.3 ||
+		len(city.Forecast) != 1 || city.Forecast[0].Max != 94.6 || city.Sun.DayLength < 15*3600 ||
+		city.Stats != nil || result.Data.Moon.Phase != "Waxing Gibbous" {
+		t.Errorf("Got %s", body)
+	}
+
+	if len(result.Errors) != 1 || strings.Join(result.Errors[0].Path, ".") != "city.stats" || result.Errors[0].Extensions["code"] != "insufficient-data" {
+		t.Errorf("Got errors %v", result.Errors)
+	}
+}
+
+func TestGraphQLRequests(t *testing.T) {
+	cache, vars := newTestCaches()
+	cache.GeoCache.AddEntry(types.Candidates{{Name: "Milan", Country: "IT", Lat: 45.46, Lon: 9.19}}, "MILAN")
+
+	schema, err := NewGraphQLSchema(cache, types.InitDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tooMany strings.Builder
+	for idx := range maxGraphQLCities + 1 {
+		fmt.Fprintf(&tooMany, `c%d: city(name: \"Milan\") { name } `, idx)
+	}
+
+	tests := []struct {
+		Name     string
+		Method   string
+		Target   string
+		Body     string
+		Status   int
+		Expected string
+	}{
+		{"POST query", http.MethodPost, "/graphql", `{"query":"query Moon { moon { icon } }","operationName":"Moon"}`, http.StatusOK, `{"data":{"moon":{"icon":"🌔"}}}`},
+		{"Malformed body", http.MethodPost, "/graphql", `{"query":`, http.StatusBadRequest, ""},
+		{"Missing query", http.MethodGet, "/graphql", "", http.StatusBadRequest, ""},
+		{"Invalid variables", http.MethodGet, "/graphql?query=%7Bmoon%7Bicon%7D%7D&variables=nope", "", http.StatusBadRequest, ""},
+		{"Too many cities", http.MethodPost, "/graphql", `{"query":"{` + tooMany.String() + `}"}`, http.StatusOK, "Too many cities"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			GetGraphQL(res, httptest.NewRequest(test.Method, test.Target, strings.NewReader(test.Body)), &schema, vars)
+
+			body := strings.TrimSpace(res.Body.String())
+			if res.Code != test.Status || !strings.Contains(body, test.Expected) {
+				t.Errorf("Got %d: %s", res.Code, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ceticamarco/zephyr/metrics"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Structure representing the outcome of the readiness checks
+type readinessReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Structure representing the build and runtime configuration
+type versionReport struct {
+	types.BuildInfo
+	Provider   string            `json:"provider"`
+	BaseURL    string            `json:"providerUrl"`
+	TimeToLive map[string]string `json:"ttl"`
+}
+
+func GetUpstreamHealth(res http.ResponseWriter, req *http.Request) {
+	jsonValue(res, map[string]any{
+		"breakers": model.Upstream.BreakerStatus(),
+	})
+}
+
+// GetHealth reports whether the process is up and serving requests
+func GetHealth(res http.ResponseWriter, req *http.Request) {
+	jsonValue(res, map[string]string{"status": "ok"})
+}
+
+// GetReadiness reports whether the service is able to handle traffic, that is
+// the statistical database has been loaded and no upstream circuit is open
+func GetReadiness(res http.ResponseWriter, req *http.Request, statDB *types.StatDB) {
+	report := readinessReport{
+		Status: "ready",
+		Checks: make(map[string]string),
+	}
+
+	if statDB.IsLoaded() {
+		report.Checks["statdb"] = "loaded"
+	} else {
+		report.Checks["statdb"] = "loading"
+		report.Status = "unavailable"
+	}
+
+	// A half-open circuit is probing the provider, hence it is considered ready
+	for endpoint, status := range model.Upstream.BreakerStatus() {
+		report.Checks["upstream:"+endpoint] = status.State
+		if status.State == model.StateOpen.String() {
+			report.Status = "unavailable"
+		}
+	}
+
+	statusCode := http.StatusOK
+	if report.Status != "ready" {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(statusCode)
+	json.NewEncoder(res).Encode(report)
+}
+
+// GetVersion reports the build information and the configured time-to-live of each cache
+func GetVersion(res http.ResponseWriter, req *http.Request, build *types.BuildInfo, vars *types.Variables) {
+	ttl := vars.TimeToLive
+	jsonValue(res, versionReport{
+		BuildInfo: *build,
+		Provider:  "OpenWeatherMap",
+		BaseURL:   model.Upstream.BaseURL,
+		TimeToLive: map[string]string{
+			"weather":   ttl.Weather.String(),
+			"metrics":   ttl.Metrics.String(),
+			"wind":      ttl.Wind.String(),
+			"forecast":  ttl.Forecast.String(),
+			"moon":      ttl.Moon.String(),
+			"geocoding": ttl.Geocoding.String(),
+		},
+	})
+}
+
+// GetInternalMetrics exposes the service metrics using the Prometheus text format
+func GetInternalMetrics(res http.ResponseWriter, req *http.Request, registry *metrics.Registry) {
+	res.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	res.WriteHeader(http.StatusOK)
+	registry.Write(res)
+}
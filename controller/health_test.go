@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetReadiness(t *testing.T) {
+	upstream := model.Upstream
+	defer func() { model.Upstream = upstream }()
+
+	model.Upstream = model.NewClient()
+	statDB := types.InitDB()
+
+	tests := []struct {
+		Name     string
+		Setup    func()
+		Expected int
+	}{
+		{"Closed circuits", func() {}, http.StatusOK},
+		{"Open circuit", func() {
+			breaker := model.Upstream.Breakers[model.ENDPOINT_ONECALL]
+			for range breaker.Threshold {
+				breaker.Record(false)
+			}
+		}, http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Setup()
+
+			res := httptest.NewRecorder()
+			GetReadiness(res, httptest.NewRequest(http.MethodGet, "/readyz", nil), statDB)
+
+			if res.Code != test.Expected {
+				t.Errorf("Got %d, wanted %d", res.Code, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ceticamarco/zephyr/openapi"
+)
+
+// GetOpenAPI returns the OpenAPI document describing the API
+func GetOpenAPI(res http.ResponseWriter, req *http.Request, doc *openapi.Document) {
+	jsonValue(res, doc)
+}
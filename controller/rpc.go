@@ -0,0 +1,367 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/rpc"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Shortest interval between two updates of a weather stream
+const minWatchInterval = time.Minute
+
+// RPCServer, implementing the gRPC service on top of the same caches,
+// statistical database and settings of the HTTP endpoints
+type RPCServer struct {
+	rpc.UnimplementedZephyrServer
+	cache     *types.Caches
+	statDB    *types.StatDB
+	vars      *atomic.Pointer[types.Variables]
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewRPCServer(cache *types.Caches, statDB *types.StatDB, vars *atomic.Pointer[types.Variables]) *RPCServer {
+	return &RPCServer{cache: cache, statDB: statDB, vars: vars, done: make(chan struct{})}
+}
+
+// Close ends the open streams, so that the gRPC server can be stopped gracefully
+func (server *RPCServer) Close() {
+	server.closeOnce.Do(func() {
+		close(server.done)
+	})
+}
+
+// Map the HTTP status of the API errors to gRPC status codes
+var rpcCodes = map[int]codes.Code{
+	http.StatusMultipleChoices:     codes.InvalidArgument,
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusUnprocessableEntity: codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusBadGateway:          codes.Unavailable,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// toRPCError converts an error into a gRPC status, carrying the
+// error code of the HTTP API(e.g. 'city-not-found') as its reason
+func toRPCError(err error) error {
+	apiErr := toAPIError(err)
+
+	code, isPresent := rpcCodes[apiErr.Status]
+	if !isPresent {
+		code = codes.Internal
+	}
+
+	rpcStatus, detailsErr := status.New(code, apiErr.Detail).WithDetails(&errdetails.ErrorInfo{
+		Reason: apiErr.Code,
+		Domain: "zephyr",
+	})
+	if detailsErr != nil {
+		return status.Error(code, apiErr.Detail)
+	}
+
+	return rpcStatus.Err()
+}
+
+// WriteRPCError allows interceptors to report errors
+// using the same format of the service
+func WriteRPCError(err error) error {
+	return toRPCError(err)
+}
+
+// rpcLocation resolves the location of a request through the same
+// parameters of the HTTP endpoints(i.e. 'lat', 'lon' and 'zip')
+func rpcLocation(ctx context.Context, req *rpc.LocationRequest, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.City, bool, converter, error) {
+	conv := converter{isImperial: req.GetUnits() == rpc.UnitSystem_UNIT_SYSTEM_IMPERIAL}
+	params := url.Values{}
+	if req.GetCountry() != "" {
+		params.Set("country", req.GetCountry())
+	}
+
+	var cityName string
+	switch query := req.GetQuery().(type) {
+	case *rpc.LocationRequest_City:
+		cityName = strings.TrimSpace(query.City)
+		if cityName == "" {
+			return types.City{}, false, conv, badRequest("Missing city name")
+		}
+	case *rpc.LocationRequest_Coordinates:
+		params.Set("lat", strconv.FormatFloat(query.Coordinates.GetLat(), 'f', -1, 64))
+		params.Set("lon", strconv.FormatFloat(query.Coordinates.GetLon(), 'f', -1, 64))
+	case *rpc.LocationRequest_Zip:
+		params.Set("zip", query.Zip)
+	default:
+		return types.City{}, false, conv, badRequest("Missing city name, coordinates or postal code")
+	}
+
+	city, isResolved, err := resolveLocation(ctx, params, cityName, geoCache, vars)
+
+	return city, isResolved, conv, err
+}
+
+func toRPCUnits(units types.UnitsV2) *rpc.Units {
+	return &rpc.Units{
+		Temperature: units.Temperature,
+		Speed:       units.Speed,
+		Pressure:    units.Pressure,
+		Distance:    units.Distance,
+	}
+}
+
+// toRPCLocation returns the location of a reply, which is only
+// set when it has been resolved from coordinates or postal code
+func toRPCLocation(city types.City, isResolved bool) *rpc.Location {
+	if !isResolved {
+		return nil
+	}
+
+	return &rpc.Location{
+		Name:    city.Name,
+		Country: city.Country,
+		State:   city.State,
+		Lat:     city.Lat,
+		Lon:     city.Lon,
+	}
+}
+
+func toRPCWind(wind types.WindV2) *rpc.Wind {
+	return &rpc.Wind{
+		Arrow:     wind.Arrow,
+		Direction: wind.Direction,
+		Speed:     wind.Speed,
+	}
+}
+
+func toRPCWeather(weather types.WeatherV2) *rpc.Weather {
+	return &rpc.Weather{
+		Date:        timestamppb.New(weather.Date),
+		Temperature: weather.Temperature,
+		Condition:   weather.Condition,
+		FeelsLike:   weather.FeelsLike,
+		Emoji:       weather.Emoji,
+		Units:       toRPCUnits(weather.Units),
+	}
+}
+
+func (server *RPCServer) GetWeather(ctx context.Context, req *rpc.LocationRequest) (*rpc.Weather, error) {
+	vars := server.vars.Load()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	weather, _, err := fetchWeather(ctx, city, &server.cache.WeatherCache, server.statDB, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	reply := toRPCWeather(conv.weather(weather))
+	reply.Location = toRPCLocation(city, isResolved)
+
+	return reply, nil
+}
+
+func (server *RPCServer) GetMetrics(ctx context.Context, req *rpc.LocationRequest) (*rpc.Metrics, error) {
+	vars := server.vars.Load()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	metrics, _, err := getData(ctx, &server.cache.MetricsCache, locationKey(city), vars.TimeToLive.Metrics, func(ctx context.Context) (types.Metrics, error) {
+		return model.GetMetrics(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	result := conv.metrics(metrics)
+
+	return &rpc.Metrics{
+		Humidity:   int32(result.Humidity),
+		Pressure:   int32(result.Pressure),
+		DewPoint:   result.DewPoint,
+		UvIndex:    int32(result.UvIndex),
+		Visibility: result.Visibility,
+		Units:      toRPCUnits(result.Units),
+		Location:   toRPCLocation(city, isResolved),
+	}, nil
+}
+
+func (server *RPCServer) GetWind(ctx context.Context, req *rpc.LocationRequest) (*rpc.CurrentWind, error) {
+	vars := server.vars.Load()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	wind, _, err := getData(ctx, &server.cache.WindCache, locationKey(city), vars.TimeToLive.Wind, func(ctx context.Context) (types.Wind, error) {
+		return model.GetWind(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return &rpc.CurrentWind{
+		Wind:     toRPCWind(conv.wind(wind)),
+		Units:    toRPCUnits(conv.units()),
+		Location: toRPCLocation(city, isResolved),
+	}, nil
+}
+
+func (server *RPCServer) GetForecast(ctx context.Context, req *rpc.LocationRequest) (*rpc.Forecast, error) {
+	vars := server.vars.Load()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	forecast, _, err := getData(ctx, &server.cache.ForecastCache, locationKey(city), vars.TimeToLive.Forecast, func(ctx context.Context) (types.Forecast, error) {
+		return model.GetForecast(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	result := conv.forecast(forecast)
+	reply := &rpc.Forecast{
+		Forecast: make([]*rpc.ForecastEntity, 0, len(result.Forecast)),
+		Units:    toRPCUnits(result.Units),
+		Location: toRPCLocation(city, isResolved),
+	}
+
+	for _, val := range result.Forecast {
+		reply.Forecast = append(reply.Forecast, &rpc.ForecastEntity{
+			Date:      timestamppb.New(val.Date),
+			Min:       val.Min,
+			Max:       val.Max,
+			Condition: val.Condition,
+			Emoji:     val.Emoji,
+			FeelsLike: val.FeelsLike,
+			Wind:      toRPCWind(val.Wind),
+		})
+	}
+
+	return reply, nil
+}
+
+func (server *RPCServer) GetMoon(ctx context.Context, req *rpc.MoonRequest) (*rpc.Moon, error) {
+	vars := server.vars.Load()
+
+	moon, _, err := getData(ctx, &server.cache.MoonCache, types.MoonKey, vars.TimeToLive.Moon, func(ctx context.Context) (types.Moon, error) {
+		return model.GetMoon(ctx, vars.Token)
+	})
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	result := toMoonV2(moon)
+
+	return &rpc.Moon{
+		Icon:       result.Icon,
+		Phase:      result.Phase,
+		Percentage: int32(result.Percentage),
+	}, nil
+}
+
+func (server *RPCServer) GetStatistics(ctx context.Context, req *rpc.LocationRequest) (*rpc.Statistics, error) {
+	vars := server.vars.Load()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, vars)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	stats, err := model.GetStatistics(locationKey(city), server.statDB)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	result := conv.statistics(stats)
+	reply := &rpc.Statistics{
+		Min:       result.Min,
+		Max:       result.Max,
+		Count:     int32(result.Count),
+		Mean:      result.Mean,
+		StdDev:    result.StdDev,
+		Median:    result.Median,
+		Mode:      result.Mode,
+		Anomalies: make([]*rpc.WeatherAnomaly, 0, len(result.Anomalies)),
+		Units:     toRPCUnits(result.Units),
+		Location:  toRPCLocation(city, isResolved),
+	}
+
+	for _, val := range result.Anomalies {
+		reply.Anomalies = append(reply.Anomalies, &rpc.WeatherAnomaly{
+			Date:        timestamppb.New(val.Date),
+			Temperature: val.Temperature,
+		})
+	}
+
+	return reply, nil
+}
+
+// WatchWeather sends the weather of a location and then waits for the
+// cached observation to expire, sending the new one whenever it has been
+// fetched. Clients share the cache, hence a stream does not add upstream
+// calls besides the ones required to keep the cache up to date
+func (server *RPCServer) WatchWeather(req *rpc.LocationRequest, stream grpc.ServerStreamingServer[rpc.Weather]) error {
+	ctx := stream.Context()
+
+	city, isResolved, conv, err := rpcLocation(ctx, req, &server.cache.GeoCache, server.vars.Load())
+	if err != nil {
+		return toRPCError(err)
+	}
+
+	var lastFetchedAt time.Time
+	for {
+		vars := server.vars.Load()
+
+		weather, fetchedAt, err := fetchWeather(ctx, city, &server.cache.WeatherCache, server.statDB, vars)
+		if err != nil {
+			return toRPCError(err)
+		}
+
+		if !fetchedAt.Equal(lastFetchedAt) {
+			reply := toRPCWeather(conv.weather(weather))
+			reply.Location = toRPCLocation(city, isResolved)
+
+			if err := stream.Send(reply); err != nil {
+				return err
+			}
+			lastFetchedAt = fetchedAt
+		}
+
+		// Stale observations are checked again after the shortest interval
+		wait := max(time.Until(fetchedAt.Add(vars.TimeToLive.Weather)), minWatchInterval)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-server.done:
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/rpc"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// newTestClient serves the gRPC service over an in-memory connection
+func newTestClient(t *testing.T, options ...grpc.ServerOption) (rpc.ZephyrClient, *RPCServer) {
+	cache, vars := newTestCaches()
+
+	var current atomic.Pointer[types.Variables]
+	current.Store(vars)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(options...)
+	service := NewRPCServer(cache, types.InitDB(), &current)
+	rpc.RegisterZephyrServer(server, service)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		service.Close()
+		server.Stop()
+	})
+
+	return rpc.NewZephyrClient(conn), service
+}
+
+var milan = &rpc.LocationRequest{Query: &rpc.LocationRequest_Coordinates{Coordinates: &rpc.Coordinates{Lat: 45.46, Lon: 9.19}}}
+
+func TestRPCWeather(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	weather, err := client.GetWeather(context.Background(), &rpc.LocationRequest{
+		Query: milan.Query,
+		Units: rpc.UnitSystem_UNIT_SYSTEM_IMPERIAL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weather.GetTemperature() != 91.6 || weather.GetUnits().GetTemperature() != "°F" || weather.GetLocation().GetName() != "Milan" {
+		t.Errorf("Got %v", weather)
+	}
+
+}
+
+func TestRPCWatchWeather(t *testing.T) {
+	// Report when the streaming handler returns on the server side
+	finished := make(chan error, 1)
+	client, service := newTestClient(t, grpc.StreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		finished <- err
+
+		return err
+	}))
+
+	// watch opens a stream and checks that it starts with the cached observation
+	watch := func(ctx context.Context) grpc.ServerStreamingClient[rpc.Weather] {
+		stream, err := client.WatchWeather(ctx, milan)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if update.GetTemperature() != 33.1 || update.GetLocation().GetName() != "Milan" {
+			t.Errorf("Got %v", update)
+		}
+
+		return stream
+	}
+
+	t.Run("Client cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := watch(ctx)
+		cancel()
+
+		if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+			t.Errorf("Got %v, wanted %s", err, codes.Canceled)
+		}
+
+		select {
+		case err := <-finished:
+			if err != nil {
+				t.Errorf("Got %v, wanted the handler to return cleanly", err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Got no return, wanted the handler to stop watching")
+		}
+	})
+
+	t.Run("Server shutdown", func(t *testing.T) {
+		stream := watch(context.Background())
+		service.Close()
+
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Errorf("Got %v, wanted the stream to end", err)
+		}
+		<-finished
+	})
+}
+
+func TestRPCErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Err    error
+		Code   codes.Code
+		Reason string
+	}{
+		{"Invalid request", badRequest("Missing city name"), codes.InvalidArgument, "invalid-request"},
+		{"Unknown city", model.ErrCityNotFound, codes.NotFound, "city-not-found"},
+		{"Insufficient statistics", model.ErrInsufficientData, codes.FailedPrecondition, "insufficient-data"},
+		{"Upstream down", &model.UpstreamError{Err: model.ErrUpstreamDown}, codes.Unavailable, "upstream-unavailable"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := status.Convert(toRPCError(test.Err))
+
+			var reason string
+			for _, detail := range got.Details() {
+				if info, isInfo := detail.(*errdetails.ErrorInfo); isInfo {
+					reason = info.GetReason()
+				}
+			}
+
+			if got.Code() != test.Code || reason != test.Reason {
+				t.Errorf("Got (%s, %s), wanted (%s, %s)", got.Code(), reason, test.Code, test.Reason)
+			}
+		})
+	}
+
+	// Requests without a location are refused before reaching the caches
+	client, _ := newTestClient(t)
+	_, err := client.GetWind(context.Background(), &rpc.LocationRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Got %v, wanted %s", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,342 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Units of measurement of the '/v2/' API
+var (
+	metricUnits = types.UnitsV2{
+		Temperature: "°C",
+		Speed:       "km/h",
+		Pressure:    "hPa",
+		Distance:    "km",
+	}
+	imperialUnits = types.UnitsV2{
+		Temperature: "°F",
+		Speed:       "mph",
+		Pressure:    "hPa",
+		Distance:    "mi",
+	}
+)
+
+// converter turns the metric values stored by the caches(e.g. '33.12' °C,
+// '4.60' m/s) into the numbers returned by the '/v2/' API
+type converter struct {
+	isImperial bool
+}
+
+// unitsParam returns the converter of the system of units
+// requested through the 'units' query parameter
+func unitsParam(req *http.Request) (converter, error) {
+	switch units := req.URL.Query().Get("units"); units {
+	case "", "metric":
+		return converter{isImperial: false}, nil
+	case "imperial":
+		return converter{isImperial: true}, nil
+	default:
+		return converter{}, badRequest(fmt.Sprintf("Unknown units '%s', expected 'metric' or 'imperial'", units))
+	}
+}
+
+func roundTo(val float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+
+	return math.Round(val*scale) / scale
+}
+
+func parseNumber(val string) float64 {
+	parsedVal, _ := strconv.ParseFloat(val, 64)
+
+	return parsedVal
+}
+
+func (conv converter) units() types.UnitsV2 {
+	if conv.isImperial {
+		return imperialUnits
+	}
+
+	return metricUnits
+}
+
+func (conv converter) temperature(temp string) float64 {
+	parsedTemp := parseNumber(temp)
+	if conv.isImperial {
+		parsedTemp = parsedTemp*9/5 + 32
+	}
+
+	return roundTo(parsedTemp, 1)
+}
+
+// deviation converts a temperature difference, which
+// is scaled but not shifted between the two scales
+func (conv converter) deviation(stdDev string) float64 {
+	parsedStdDev := parseNumber(stdDev)
+	if conv.isImperial {
+		parsedStdDev = parsedStdDev * 9 / 5
+	}
+
+	return roundTo(parsedStdDev, 2)
+}
+
+func (conv converter) speed(windSpeed string) float64 {
+	// 1 m/s = 2.23694 mph
+	// 1 m/s = 3.6 km/h
+	parsedSpeed := parseNumber(windSpeed)
+	if conv.isImperial {
+		return roundTo(parsedSpeed*2.23694, 1)
+	}
+
+	return roundTo(parsedSpeed*3.6, 1)
+}
+
+func (conv converter) distance(dist string) float64 {
+	// 1 km = 0.621371 mi
+	parsedDist := parseNumber(dist)
+	if conv.isImperial {
+		parsedDist *= 0.621371
+	}
+
+	return roundTo(parsedDist, 1)
+}
+
+func (conv converter) wind(wind types.Wind) types.WindV2 {
+	return types.WindV2{
+		Arrow:     wind.Arrow,
+		Direction: wind.Direction,
+		Speed:     conv.speed(wind.Speed),
+	}
+}
+
+func (conv converter) weather(weather types.Weather) types.WeatherV2 {
+	return types.WeatherV2{
+		Date:        weather.Date.Date,
+		Temperature: conv.temperature(weather.Temperature),
+		Condition:   weather.Condition,
+		FeelsLike:   conv.temperature(weather.FeelsLike),
+		Emoji:       weather.Emoji,
+		Units:       conv.units(),
+	}
+}
+
+func (conv converter) metrics(metrics types.Metrics) types.MetricsV2 {
+	return types.MetricsV2{
+		Humidity:   int(parseNumber(metrics.Humidity)),
+		Pressure:   int(parseNumber(metrics.Pressure)),
+		DewPoint:   conv.temperature(metrics.DewPoint),
+		UvIndex:    int(parseNumber(metrics.UvIndex)),
+		Visibility: conv.distance(metrics.Visibility),
+		Units:      conv.units(),
+	}
+}
+
+// forecast converts a cached forecast, which is only read
+// and hence does not need to be copied
+func (conv converter) forecast(forecast types.Forecast) types.ForecastV2 {
+	result := types.ForecastV2{
+		Forecast: make([]types.ForecastEntityV2, 0, len(forecast.Forecast)),
+		Units:    conv.units(),
+	}
+
+	for _, val := range forecast.Forecast {
+		result.Forecast = append(result.Forecast, types.ForecastEntityV2{
+			Date:      val.Date.Date,
+			Min:       conv.temperature(val.Min),
+			Max:       conv.temperature(val.Max),
+			Condition: val.Condition,
+			Emoji:     val.Emoji,
+			FeelsLike: conv.temperature(val.FeelsLike),
+			Wind:      conv.wind(val.Wind),
+		})
+	}
+
+	return result
+}
+
+// statistics converts statistics, whose anomalies are
+// always encoded as an array even when there are none
+func (conv converter) statistics(stats types.StatResult) types.StatResultV2 {
+	result := types.StatResultV2{
+		Min:       conv.temperature(stats.Min),
+		Max:       conv.temperature(stats.Max),
+		Count:     stats.Count,
+		Mean:      conv.temperature(stats.Mean),
+		StdDev:    conv.deviation(stats.StdDev),
+		Median:    conv.temperature(stats.Median),
+		Mode:      conv.temperature(stats.Mode),
+		Anomalies: []types.WeatherAnomalyV2{},
+		Units:     conv.units(),
+	}
+
+	if stats.Anomaly != nil {
+		for _, val := range *stats.Anomaly {
+			result.Anomalies = append(result.Anomalies, types.WeatherAnomalyV2{
+				Date:        val.Date.Date,
+				Temperature: conv.temperature(val.Temp),
+			})
+		}
+	}
+
+	return result
+}
+
+func toMoonV2(moon types.Moon) types.MoonV2 {
+	return types.MoonV2{
+		Icon:       moon.Icon,
+		Phase:      moon.Phase,
+		Percentage: int(parseNumber(moon.Percentage)),
+	}
+}
+
+// locatedV2 parses the city name, the units and the location
+// shared by the located endpoints of the '/v2/' API
+func locatedV2(req *http.Request, geoCache *types.Cache[types.Candidates], vars *types.Variables) (types.City, bool, converter, error) {
+	cityName, err := cityParam(req)
+	if err != nil {
+		return types.City{}, false, converter{}, err
+	}
+
+	conv, err := unitsParam(req)
+	if err != nil {
+		return types.City{}, false, converter{}, err
+	}
+
+	city, isResolved, err := getLocation(req, cityName, geoCache, vars)
+
+	return city, isResolved, conv, err
+}
+
+func GetWeatherV2(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Weather], geoCache *types.Cache[types.Candidates], statDB *types.StatDB, vars *types.Variables) {
+	city, isResolved, conv, err := locatedV2(req, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	weather, fetchedAt, err := fetchWeather(req.Context(), city, cache, statDB, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	result := conv.weather(weather)
+
+	if isResolved {
+		result.Location = &city
+	}
+
+	jsonCached(res, req, result, fetchedAt, vars.TimeToLive.Weather)
+}
+
+func GetMetricsV2(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Metrics], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	city, isResolved, conv, err := locatedV2(req, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	metrics, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Metrics, func(ctx context.Context) (types.Metrics, error) {
+		return model.GetMetrics(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	result := conv.metrics(metrics)
+
+	if isResolved {
+		result.Location = &city
+	}
+
+	jsonCached(res, req, result, fetchedAt, vars.TimeToLive.Metrics)
+}
+
+func GetWindV2(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Wind], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	city, isResolved, conv, err := locatedV2(req, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	wind, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Wind, func(ctx context.Context) (types.Wind, error) {
+		return model.GetWind(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	result := types.CurrentWindV2{WindV2: conv.wind(wind), Units: conv.units()}
+
+	if isResolved {
+		result.Location = &city
+	}
+
+	jsonCached(res, req, result, fetchedAt, vars.TimeToLive.Wind)
+}
+
+func GetForecastV2(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Forecast], geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	city, isResolved, conv, err := locatedV2(req, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	forecast, fetchedAt, err := getData(req.Context(), cache, locationKey(city), vars.TimeToLive.Forecast, func(ctx context.Context) (types.Forecast, error) {
+		return model.GetForecast(ctx, &city, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	result := conv.forecast(forecast)
+
+	if isResolved {
+		result.Location = &city
+	}
+
+	jsonCached(res, req, result, fetchedAt, vars.TimeToLive.Forecast)
+}
+
+func GetMoonV2(res http.ResponseWriter, req *http.Request, cache *types.Cache[types.Moon], vars *types.Variables) {
+	moon, fetchedAt, err := getData(req.Context(), cache, types.MoonKey, vars.TimeToLive.Moon, func(ctx context.Context) (types.Moon, error) {
+		return model.GetMoon(ctx, vars.Token)
+	})
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	jsonCached(res, req, toMoonV2(moon), fetchedAt, vars.TimeToLive.Moon)
+}
+
+func GetStatisticsV2(res http.ResponseWriter, req *http.Request, statDB *types.StatDB, geoCache *types.Cache[types.Candidates], vars *types.Variables) {
+	city, isResolved, conv, err := locatedV2(req, geoCache, vars)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	stats, err := model.GetStatistics(locationKey(city), statDB)
+	if err != nil {
+		jsonProblem(res, req, err)
+		return
+	}
+
+	result := conv.statistics(stats)
+
+	if isResolved {
+		result.Location = &city
+	}
+
+	jsonValue(res, result)
+}
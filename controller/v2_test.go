@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/config"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// newTestCaches returns caches holding the data of Milan, which can be
+// queried through '?lat=45.46&lon=9.19' without reaching the upstream provider
+func newTestCaches() (*types.Caches, *types.Variables) {
+	date := types.ZephyrDate{Date: time.Date(2025, 6, 19, 12, 0, 0, 0, time.UTC)}
+	wind := types.Wind{Arrow: "↓", Direction: "N", Speed: "4.60"}
+
+	cache := types.InitCache()
+	cache.GeoCache.AddEntry(types.Candidates{{Name: "Milan", Country: "IT"}}, "COORDS:45.46,9.19")
+	cache.WeatherCache.AddEntry(types.Weather{
+		Date:        date,
+		Temperature: "33.12",
+		Condition:   "Clear",
+		FeelsLike:   "35.6",
+		Emoji:       "☀️",
+	}, "45.46,9.19")
+	cache.MetricsCache.AddEntry(types.Metrics{
+		Humidity:   "41",
+		Pressure:   "1015",
+		DewPoint:   "18.4",
+		UvIndex:    "7",
+		Visibility: "10",
+	}, "45.46,9.19")
+	cache.WindCache.AddEntry(wind, "45.46,9.19")
+	cache.ForecastCache.AddEntry(types.Forecast{Forecast: []types.ForecastEntity{{
+		Date:      date,
+		Min:       "21.3",
+		Max:       "34.8",
+		Condition: "Clear",
+		Emoji:     "☀️",
+		FeelsLike: "36.2",
+		Wind:      wind,
+	}}}, "45.46,9.19")
+	cache.MoonCache.AddEntry(types.Moon{Icon: "🌔", Phase: "Waxing Gibbous", Percentage: "82"}, types.MoonKey)
+
+	vars := &types.Variables{TimeToLive: types.TimeToLive{
+		Weather:   time.Hour,
+		Metrics:   time.Hour,
+		Wind:      time.Hour,
+		Forecast:  time.Hour,
+		Moon:      time.Hour,
+		Geocoding: time.Hour,
+	}}
+
+	return cache, vars
+}
+
+// serve calls a handler and returns its body without the trailing newline
+func serve(target string, handler func(http.ResponseWriter, *http.Request)) (int, string) {
+	res := httptest.NewRecorder()
+	handler(res, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return res.Code, strings.TrimSpace(res.Body.String())
+}
+
+// TestV1Responses freezes the string-valued responses documented by
+// the README, which existing clients parse as they are
+func TestV1Responses(t *testing.T) {
+	cache, vars := newTestCaches()
+	statDB := types.InitDB()
+	location := `"location":{"name":"Milan","country":"IT","lat":45.46,"lon":9.19}`
+
+	tests := []struct {
+		Target   string
+		Handler  func(http.ResponseWriter, *http.Request)
+		Expected string
+	}{
+		{"/weather/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetWeather(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars)
+		}, `{"date":"Thursday, 2025/06/19","temperature":"33°C","condition":"Clear","feelsLike":"36°C","emoji":"☀️",` + location + `}`},
+		{"/weather/?lat=45.46&lon=9.19&i", func(res http.ResponseWriter, req *http.Request) {
+			GetWeather(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars)
+		}, `{"date":"Thursday, 2025/06/19","temperature":"65°F","condition":"Clear","feelsLike":"68°F","emoji":"☀️",` + location + `}`},
+		{"/metrics/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetMetrics(res, req, &cache.MetricsCache, &cache.GeoCache, vars)
+		}, `{"humidity":"41%","pressure":"1015 hPa","dewPoint":"18°C","uvIndex":"7","visibility":"10km",` + location + `}`},
+		{"/wind/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetWind(res, req, &cache.WindCache, &cache.GeoCache, vars)
+		}, `{"arrow":"↓","direction":"N","speed":"16.6 km/h",` + location + `}`},
+		{"/wind/?lat=45.46&lon=9.19&i", func(res http.ResponseWriter, req *http.Request) {
+			GetWind(res, req, &cache.WindCache, &cache.GeoCache, vars)
+		}, `{"arrow":"↓","direction":"N","speed":"10.3 mph",` + location + `}`},
+		{"/forecast/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetForecast(res, req, &cache.ForecastCache, &cache.GeoCache, vars)
+		}, `{"forecast":[{"date":"Thursday, 2025/06/19","min":"21°C","max":"35°C","condition":"Clear","emoji":"☀️","feelsLike":"36°C","wind":{"arrow":"↓","direction":"N","speed":"16.6 km/h"}}],` + location + `}`},
+		{"/moon", func(res http.ResponseWriter, req *http.Request) {
+			GetMoon(res, req, &cache.MoonCache, vars)
+		}, `{"icon":"🌔","phase":"Waxing Gibbous","percentage":"82%"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Target, func(t *testing.T) {
+			status, body := serve(test.Target, test.Handler)
+
+			if status != http.StatusOK || body != test.Expected {
+				t.Errorf("Got (%d, %s), wanted %s", status, body, test.Expected)
+			}
+		})
+	}
+
+	// Cached values must not be altered by the formatting
+	if cached, _ := cache.WindCache.GetEntry("45.46,9.19", time.Hour); cached.Speed != "4.60" {
+		t.Errorf("Got %s, wanted the cached speed to be unchanged", cached.Speed)
+	}
+}
+
+// TestV1CityNames checks that unqualified names matching several places
+// resolve to the most relevant one, unless strict geocoding is enabled
+func TestV1CityNames(t *testing.T) {
+	cache, vars := newTestCaches()
+	vars.StrictGeocoding = config.Default().StrictGeocoding
+	cache.GeoCache.AddEntry(types.Candidates{
+		{Name: "Paris", Country: "FR", Lat: 48.86, Lon: 2.35},
+		{Name: "Paris", Country: "US", State: "Texas", Lat: 33.66, Lon: -95.56},
+	}, "PARIS")
+	cache.WeatherCache.AddEntry(types.Weather{
+		Date:        types.ZephyrDate{Date: time.Date(2025, 6, 19, 12, 0, 0, 0, time.UTC)},
+		Temperature: "24.8",
+		Condition:   "Clouds",
+		FeelsLike:   "25.1",
+		Emoji:       "☁️",
+	}, "48.86,2.35")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /weather/{city...}", func(res http.ResponseWriter, req *http.Request) {
+		GetWeather(res, req, &cache.WeatherCache, &cache.GeoCache, types.InitDB(), vars)
+	})
+	handler := mux.ServeHTTP
+
+	status, body := serve("/weather/paris", handler)
+	if expected := `{"date":"Thursday, 2025/06/19","temperature":"25°C","condition":"Clouds","feelsLike":"25°C","emoji":"☁️"}`; status != http.StatusOK || body != expected {
+		t.Errorf("Got (%d, %s), wanted %s", status, body, expected)
+	}
+
+	vars.StrictGeocoding = true
+	if status, body := serve("/weather/paris", handler); status != http.StatusMultipleChoices || !strings.Contains(body, "ambiguous-city") {
+		t.Errorf("Got (%d, %s), wanted an ambiguous city", status, body)
+	}
+}
+
+func TestV2Responses(t *testing.T) {
+	cache, vars := newTestCaches()
+	statDB := types.InitDB()
+	metric := `"units":{"temperature":"°C","speed":"km/h","pressure":"hPa","distance":"km"}`
+	imperial := `"units":{"temperature":"°F","speed":"mph","pressure":"hPa","distance":"mi"}`
+	location := `"location":{"name":"Milan","country":"IT","lat":45.46,"lon":9.19}`
+
+	tests := []struct {
+		Target   string
+		Handler  func(http.ResponseWriter, *http.Request)
+		Expected string
+	}{
+		{"/v2/weather/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetWeatherV2(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars)
+		}, `{"date":"2025-06-19T12:00:00Z","temperature":33.1,"condition":"Clear","feelsLike":35.6,"emoji":"☀️",` + metric + `,` + location + `}`},
+		{"/v2/weather/?lat=45.46&lon=9.19&units=imperial", func(res http.ResponseWriter, req *http.Request) {
+			GetWeatherV2(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars)
+		}, `{"date":"2025-06-19T12:00:00Z","temperature":91.6,"condition":"Clear","feelsLike":96.1,"emoji":"☀️",` + imperial + `,` + location + `}`},
+		{"/v2/metrics/?lat=45.46&lon=9.19&units=imperial", func(res http.ResponseWriter, req *http.Request) {
+			GetMetricsV2(res, req, &cache.MetricsCache, &cache.GeoCache, vars)
+		}, `{"humidity":41,"pressure":1015,"dewPoint":65.1,"uvIndex":7,"visibility":6.2,` + imperial + `,` + location + `}`},
+		{"/v2/wind/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetWindV2(res, req, &cache.WindCache, &cache.GeoCache, vars)
+		}, `{"arrow":"↓","direction":"N","speed":16.6,` + metric + `,` + location + `}`},
+		{"/v2/forecast/?lat=45.46&lon=9.19", func(res http.ResponseWriter, req *http.Request) {
+			GetForecastV2(res, req, &cache.ForecastCache, &cache.GeoCache, vars)
+		}, `{"forecast":[{"date":"2025-06-19T12:00:00Z","min":21.3,"max":34.8,"condition":"Clear","emoji":"☀️","feelsLike":36.2,"wind":{"arrow":"↓","direction":"N","speed":16.6}}],` + metric + `,` + location + `}`},
+		{"/v2/moon", func(res http.ResponseWriter, req *http.Request) {
+			GetMoonV2(res, req, &cache.MoonCache, vars)
+		}, `{"icon":"🌔","phase":"Waxing Gibbous","percentage":82}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Target, func(t *testing.T) {
+			status, body := serve(test.Target, test.Handler)
+
+			if status != http.StatusOK || body != test.Expected {
+				t.Errorf("Got (%d, %s), wanted %s", status, body, test.Expected)
+			}
+		})
+	}
+}
+
+func TestUnitsParam(t *testing.T) {
+	tests := []struct {
+		Query      string
+		IsImperial bool
+		IsValid    bool
+	}{
+		{"", false, true},
+		{"?units=metric", false, true},
+		{"?units=imperial", true, true},
+		{"?units=kelvin", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Query, func(t *testing.T) {
+			conv, err := unitsParam(httptest.NewRequest(http.MethodGet, "/v2/weather/milan"+test.Query, nil))
+
+			if (err == nil) != test.IsValid || conv.isImperial != test.IsImperial {
+				t.Errorf("Got (%v, %v), wanted %v", conv.isImperial, err, test.IsImperial)
+			}
+		})
+	}
+}
+
+func TestConverter(t *testing.T) {
+	metric, imperial := converter{isImperial: false}, converter{isImperial: true}
+
+	tests := []struct {
+		Name     string
+		Got      float64
+		Expected float64
+	}{
+		{"Freezing point", imperial.temperature("0"), 32},
+		{"Boiling point", imperial.temperature("100"), 212},
+		{"Rounded temperature", metric.temperature("21.25"), 21.3},
+		{"Deviation", imperial.deviation("2.5"), 4.5},
+		{"Speed", metric.speed("10"), 36},
+		{"Imperial speed", imperial.speed("10"), 22.4},
+		{"Distance", imperial.distance("10"), 6.2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Got != test.Expected {
+				t.Errorf("Got %v, wanted %v", test.Got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestJsonErrorV2(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v2/stats/milan", nil)
+	req = req.WithContext(types.WithRequestID(req.Context(), "abc123"))
+	res := httptest.NewRecorder()
+
+	jsonProblem(res, req, model.ErrInsufficientData)
+
+	if got := res.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Got %s, wanted application/json", got)
+	}
+
+	var envelope struct {
+		Error map[string]any `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if envelope.Error["code"] != "insufficient-data" || envelope.Error["requestId"] != "abc123" || envelope.Error["status"] != float64(http.StatusUnprocessableEntity) {
+		t.Errorf("Got %v", envelope.Error)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ceticamarco/zephyr/config"
+)
+
+// Deadline of the health probe, shorter than the timeout of the Docker HEALTHCHECK
+const healthcheckTimeout = 2 * time.Second
+
+// probeAddress returns the local address of a listener, replacing
+// wildcard hosts(e.g. ':3000' or '0.0.0.0:3000') with the loopback one
+func probeAddress(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// pinnedCertificate returns a TLS configuration trusting only the certificate
+// served by Zephyr, whose names usually do not include the loopback address
+func pinnedCertificate(certFile string) (*tls.Config, error) {
+	content, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in '%s'", certFile)
+	}
+
+	return &tls.Config{
+		// The chain and the host name are replaced by the check below
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 || !bytes.Equal(state.PeerCertificates[0].Raw, block.Bytes) {
+				return errors.New("the server certificate does not match the configured one")
+			}
+
+			return nil
+		},
+	}, nil
+}
+
+// healthcheck probes the '/healthz' endpoint of a running server, reading
+// its address from the same configuration the server loads. The plain HTTP
+// health listener is preferred, since mutual TLS refuses the probe
+func healthcheck(cfg *config.Config) error {
+	scheme, listen := "http", cfg.Listen.Address
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	switch {
+	case cfg.Listen.HealthAddress != "":
+		listen = cfg.Listen.HealthAddress
+	case cfg.Listen.TLS.ClientCAFile != "":
+		return errors.New("mutual TLS requires 'listen.healthAddress' to be probed")
+	case cfg.Listen.TLS.CertFile != "":
+		tlsConfig, err := pinnedCertificate(cfg.Listen.TLS.CertFile)
+		if err != nil {
+			return err
+		}
+
+		scheme = "https"
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	address, err := probeAddress(listen)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: healthcheckTimeout, Transport: transport}
+	res, err := client.Get(scheme + "://" + address + "/healthz")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+
+	return nil
+}
@@ -1,62 +1,667 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ceticamarco/zephyr/config"
 	"github.com/ceticamarco/zephyr/controller"
+	"github.com/ceticamarco/zephyr/metrics"
+	"github.com/ceticamarco/zephyr/middleware"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/openapi"
+	"github.com/ceticamarco/zephyr/rpc"
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func main() {
-	// Retrieve listening port, API token and cache time-to-live from environment variables
-	var (
-		port   = os.Getenv("ZEPHYR_PORT")
-		token  = os.Getenv("ZEPHYR_TOKEN")
-		ttl, _ = strconv.ParseInt(os.Getenv("ZEPHYR_CACHE_TTL"), 10, 8)
+// Build information, injected at link time through
+// '-ldflags "-X main.version=... -X main.commit=..."'
+var (
+	version string
+	commit  string
+)
+
+// Time to drain in-flight requests, shorter than the
+// default grace period of 'docker stop'(10 seconds)
+const shutdownTimeout = 8 * time.Second
+
+// How often the configuration file is checked for changes
+const configPollInterval = 5 * time.Second
+
+// newVariables extracts the values used by the controllers from the configuration
+func newVariables(cfg *config.Config) *types.Variables {
+	return &types.Variables{
+		Token: cfg.Provider.Token,
+		TimeToLive: types.TimeToLive{
+			Weather:   time.Duration(cfg.TTL.Weather),
+			Metrics:   time.Duration(cfg.TTL.Metrics),
+			Wind:      time.Duration(cfg.TTL.Wind),
+			Forecast:  time.Duration(cfg.TTL.Forecast),
+			Moon:      time.Duration(cfg.TTL.Moon),
+			Geocoding: time.Duration(cfg.TTL.Geocoding),
+		},
+		AdminToken:      cfg.AdminToken,
+		StrictGeocoding: cfg.StrictGeocoding,
+	}
+}
+
+// newRateLimit extracts the rate limiting settings from the configuration
+func newRateLimit(cfg *config.Config) *middleware.RateLimitConfig {
+	return &middleware.RateLimitConfig{
+		RequireKey: cfg.RateLimit.RequireKey,
+		TrustProxy: cfg.RateLimit.TrustProxy,
+		IPRate:     cfg.RateLimit.IP,
+		KeyRate:    cfg.RateLimit.Key,
+	}
+}
+
+// newTLSConfig configures HTTPS, HTTP/2 and, when a client certificate
+// authority is set, mutual TLS. The certificate is served through a
+// store, so that it can be replaced without restarting the server
+func newTLSConfig(cfg *config.TLSConfig, certStore *types.CertStore) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certStore.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if cfg.ClientCAFile != "" {
+		clientCAs, err := types.LoadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// newCORS extracts the cross-origin settings from the configuration
+func newCORS(cfg *config.Config) *middleware.CORSConfig {
+	return &middleware.CORSConfig{
+		AllowedOrigins: cfg.CORS.AllowedOrigins,
+		MaxAge:         time.Duration(cfg.CORS.MaxAge),
+	}
+}
+
+// registerMetrics exposes the state of caches, quota and statistical database
+func registerMetrics(registry *metrics.Registry, cache *types.Caches, statDB *types.StatDB, budget *types.Budget, vars *atomic.Pointer[types.Variables]) {
+	cacheStats := func(value func(types.CacheKind) float64) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			var samples []metrics.Sample
+			for name, kind := range cache.Kinds(vars.Load().TimeToLive) {
+				samples = append(samples, metrics.Sample{Labels: []string{name}, Value: value(kind)})
+			}
+
+			return samples
+		}
+	}
+
+	registry.Register(
+		metrics.NewCounterFunc("zephyr_cache_hits_total", "Number of cache hits by data kind.",
+			cacheStats(func(kind types.CacheKind) float64 { return float64(kind.Cache.Stats().Hits) }), "kind"),
+		metrics.NewCounterFunc("zephyr_cache_misses_total", "Number of cache misses by data kind.",
+			cacheStats(func(kind types.CacheKind) float64 { return float64(kind.Cache.Stats().Misses) }), "kind"),
+		metrics.NewCounterFunc("zephyr_cache_evictions_total", "Number of cache evictions by data kind.",
+			cacheStats(func(kind types.CacheKind) float64 { return float64(kind.Cache.Stats().Evictions) }), "kind"),
+		metrics.NewGaugeFunc("zephyr_cache_entries", "Number of cache entries by data kind.",
+			cacheStats(func(kind types.CacheKind) float64 { return float64(len(kind.Cache.Entries(kind.TTL))) }), "kind"),
+		metrics.NewGaugeFunc("zephyr_quota_calls", "Upstream calls performed within the current day.", func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(budget.Usage().Calls)}}
+		}),
+		metrics.NewGaugeFunc("zephyr_quota_remaining", "Upstream calls left within the current day.", func() []metrics.Sample {
+			return []metrics.Sample{{Value: float64(budget.Usage().Remaining)}}
+		}),
+		metrics.NewGaugeFunc("zephyr_statdb_records", "Number of records of the statistical database.", func() []metrics.Sample {
+			records, _ := statDB.Size()
+			return []metrics.Sample{{Value: float64(records)}}
+		}),
+		metrics.NewGaugeFunc("zephyr_statdb_locations", "Number of locations of the statistical database.", func() []metrics.Sample {
+			_, locations := statDB.Size()
+			return []metrics.Sample{{Value: float64(locations)}}
+		}),
 	)
+}
+
+func main() {
+	configPath := flag.String("config", os.Getenv("ZEPHYR_CONFIG"), "Path of the JSON configuration file")
+	checkConfig := flag.Bool("check-config", false, "Validate the configuration and exit")
+	probe := flag.Bool("healthcheck", false, "Probe the health endpoint of the running server and exit")
+	flag.Parse()
+
+	// Load the configuration file(if any) and the environment variables
+	cfg, err := config.Load(*configPath)
+	if *checkConfig {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 
-	if port == "" || token == "" || ttl == 0 {
-		log.Fatalf("Environment variables not set")
+		fmt.Println("Configuration is valid")
+		return
+	}
+
+	// Exit with a non-zero status when the server is down(e.g. for the Docker HEALTHCHECK)
+	if *probe {
+		if err == nil {
+			err = healthcheck(&cfg)
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Write structured JSON logs to the standard output
+	var logLevel slog.LevelVar
+	logLevel.Set(cfg.Level())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevel}))
+	slog.SetDefault(logger)
+
+	// Initialize the daily budget of upstream calls
+	dataDir := cfg.Storage.DataDir
+	budget, err := types.InitBudget(filepath.Join(dataDir, "budget.json"), cfg.Quota.Soft, cfg.Quota.Hard)
+	if err != nil {
+		log.Fatalf("Cannot load the budget file: %v", err)
+	}
+
+	// Configure the upstream client and its circuit breakers
+	model.Upstream.Budget = budget
+	model.Upstream.BaseURL = cfg.Provider.BaseURL
+	model.Upstream.Timeout = time.Duration(cfg.Provider.Timeout)
+	model.Upstream.MaxRetries = cfg.Provider.MaxRetries
+
+	for _, breaker := range model.Upstream.Breakers {
+		breaker.Configure(cfg.Provider.Breaker.Threshold, time.Duration(cfg.Provider.Breaker.Cooldown))
+	}
+
+	// Initialize client API keys and rate limits
+	keys, err := types.InitKeyStore(cfg.KeysFile())
+	if err != nil {
+		log.Fatalf("Cannot load the API keys file: %v", err)
+	}
+
+	var rateLimit atomic.Pointer[middleware.RateLimitConfig]
+	rateLimit.Store(newRateLimit(&cfg))
+
+	var cors atomic.Pointer[middleware.CORSConfig]
+	cors.Store(newCORS(&cfg))
+
+	limiter := middleware.NewLimiter()
+	limit := func(handler http.HandlerFunc) http.Handler {
+		return middleware.RateLimit(handler, keys, limiter, &rateLimit)
 	}
 
 	// Initialize cache, statDB and vars
 	cache := types.InitCache()
 	statDB := types.InitDB()
-	vars := types.Variables{
-		Token:      token,
-		TimeToLive: int8(ttl),
+
+	// Restore the state saved on the last shutdown
+	cacheSnapshot := filepath.Join(dataDir, "cache.json")
+	statDBSnapshot := filepath.Join(dataDir, "statdb.json")
+
+	if err := cache.LoadSnapshot(cacheSnapshot); err != nil {
+		slog.Error("Cannot load the cache snapshot", slog.Any("error", err))
+	}
+	if err := statDB.LoadSnapshot(statDBSnapshot); err != nil {
+		slog.Error("Cannot load the statistical database snapshot", slog.Any("error", err))
+	}
+
+	// Every request reads the variables once, hence a reload never
+	// mixes old and new values within the same request
+	var vars atomic.Pointer[types.Variables]
+	vars.Store(newVariables(&cfg))
+
+	var current atomic.Pointer[config.Config]
+	current.Store(&cfg)
+
+	build := types.ReadBuildInfo(version, commit)
+	registerMetrics(metrics.Default, cache, statDB, budget, &vars)
+
+	// Stop on SIGINT and on SIGTERM(e.g. 'docker stop')
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Reload the configuration, swapping every setting that
+	// can be changed without dropping caches and connections
+	var reloadMu sync.Mutex
+	reload := func(reason string) {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+
+		newCfg, err := config.Load(*configPath)
+		if err != nil {
+			slog.Error("Cannot reload the configuration", slog.String("reason", reason), slog.Any("error", err))
+			return
+		}
+
+		changes := config.Diff(*current.Load(), newCfg)
+		if len(changes) == 0 {
+			slog.Info("Configuration reloaded, nothing changed", slog.String("reason", reason))
+			return
+		}
+
+		for _, change := range changes {
+			attrs := []any{slog.String("field", change.Field), slog.String("old", change.Old), slog.String("new", change.New)}
+			if change.Restart {
+				slog.Warn("Configuration change requires a restart", attrs...)
+			} else {
+				slog.Info("Configuration changed", attrs...)
+			}
+		}
+
+		budget.SetLimits(newCfg.Quota.Soft, newCfg.Quota.Hard)
+		for _, breaker := range model.Upstream.Breakers {
+			breaker.Configure(newCfg.Provider.Breaker.Threshold, time.Duration(newCfg.Provider.Breaker.Cooldown))
+		}
+
+		rateLimit.Store(newRateLimit(&newCfg))
+		cors.Store(newCORS(&newCfg))
+		vars.Store(newVariables(&newCfg))
+		logLevel.Set(newCfg.Level())
+		current.Store(&newCfg)
+
+		slog.Info("Configuration reloaded", slog.String("reason", reason), slog.Int("changes", len(changes)))
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	// Reload the configuration on SIGHUP
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reload("SIGHUP")
+			}
+		}
+	}()
+
+	if *configPath != "" {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			config.Watch(ctx, *configPath, configPollInterval, func() {
+				reload("file change")
+			})
+		}()
 	}
 
-	// API endpoints
-	http.HandleFunc("/weather/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetWeather(res, req, &cache.WeatherCache, statDB, &vars)
+	// Periodically evict expired cache entries that cannot be served
+	// as stale data anymore and idle rate limiting buckets, and
+	// persist the usage of the daily budget
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for _, kind := range cache.Kinds(vars.Load().TimeToLive) {
+				kind.Cache.Sweep(kind.TTL + types.StaleWindow)
+			}
+
+			// Forget idle clients
+			limiter.Sweep(10 * time.Minute)
+
+			if err := budget.Save(); err != nil {
+				slog.Error("Cannot save the budget file", slog.Any("error", err))
+			}
+		}
+	}()
+
+	// Periodically fetch the weather of watched cities. The list
+	// and the interval are read again after each refresh
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+
+		interval := time.Duration(cfg.Watch.Interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			watch := current.Load().Watch
+			for _, cityName := range watch.Cities {
+				err := controller.RefreshWeather(ctx, cityName, &cache.WeatherCache, &cache.GeoCache, statDB, vars.Load())
+				if err != nil && ctx.Err() == nil {
+					slog.Warn("Cannot refresh watched city", slog.String("city", cityName), slog.Any("error", err))
+				}
+			}
+
+			if time.Duration(watch.Interval) != interval {
+				interval = time.Duration(watch.Interval)
+				ticker.Reset(interval)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// API endpoints. GET routes also answer HEAD requests, while the
+	// trailing wildcard accepts trailing slashes and coordinate queries
+	// without a city name(e.g. '/weather/?lat=45.46&lon=9.19')
+	http.Handle("GET /weather/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWeather(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars.Load())
+	}))
+
+	http.Handle("GET /metrics/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMetrics(res, req, &cache.MetricsCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /wind/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWind(res, req, &cache.WindCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /forecast/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetForecast(res, req, &cache.ForecastCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /moon", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMoon(res, req, &cache.MoonCache, vars.Load())
+	}))
+
+	http.Handle("GET /stats/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetStatistics(res, req, statDB, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /geocode", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetGeocode(res, req, &cache.GeoCache, vars.Load())
+	}))
+
+	// Version 2 of the API endpoints, sharing the caches of the routes above
+	http.Handle("GET /v2/weather/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWeatherV2(res, req, &cache.WeatherCache, &cache.GeoCache, statDB, vars.Load())
+	}))
+
+	http.Handle("GET /v2/metrics/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMetricsV2(res, req, &cache.MetricsCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /v2/wind/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWindV2(res, req, &cache.WindCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /v2/forecast/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetForecastV2(res, req, &cache.ForecastCache, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /v2/moon", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMoonV2(res, req, &cache.MoonCache, vars.Load())
+	}))
+
+	http.Handle("GET /v2/stats/{city...}", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetStatisticsV2(res, req, statDB, &cache.GeoCache, vars.Load())
+	}))
+
+	http.Handle("GET /v2/geocode", limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetGeocode(res, req, &cache.GeoCache, vars.Load())
+	}))
+
+	// GraphQL endpoint, whose resolvers share the caches of the routes above.
+	// A query consumes a single token regardless of the fields it resolves
+	schema, err := controller.NewGraphQLSchema(cache, statDB)
+	if err != nil {
+		log.Fatalf("Cannot build the GraphQL schema: %v", err)
+	}
+
+	graphQL := limit(func(res http.ResponseWriter, req *http.Request) {
+		controller.GetGraphQL(res, req, &schema, vars.Load())
+	})
+	http.Handle("GET /graphql", graphQL)
+	http.Handle("POST /graphql", graphQL)
+
+	// Health endpoints
+	http.HandleFunc("GET /healthz", controller.GetHealth)
+
+	http.HandleFunc("GET /readyz", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetReadiness(res, req, statDB)
+	})
+
+	http.HandleFunc("GET /version", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetVersion(res, req, &build, vars.Load())
 	})
 
-	http.HandleFunc("/metrics/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetMetrics(res, req, &cache.MetricsCache, &vars)
+	http.HandleFunc("GET /health/upstream", controller.GetUpstreamHealth)
+
+	apiDoc := openapi.Build()
+	http.HandleFunc("GET /openapi.json", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetOpenAPI(res, req, &apiDoc)
+	})
+
+	http.HandleFunc("GET /internal/metrics", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetInternalMetrics(res, req, metrics.Default)
+	})
+
+	// Admin endpoints
+	cacheReport := func(res http.ResponseWriter, req *http.Request) {
+		controller.GetCacheReport(res, req, cache, vars.Load())
+	}
+	http.HandleFunc("GET /admin/cache", cacheReport)
+	http.HandleFunc("GET /admin/cache/{kind}", cacheReport)
+
+	purgeCache := func(res http.ResponseWriter, req *http.Request) {
+		controller.PurgeCache(res, req, cache, vars.Load())
+	}
+	http.HandleFunc("DELETE /admin/cache", purgeCache)
+	http.HandleFunc("DELETE /admin/cache/{kind}", purgeCache)
+	http.HandleFunc("DELETE /admin/cache/{kind}/{key}", purgeCache)
+
+	http.HandleFunc("GET /admin/keys", func(res http.ResponseWriter, req *http.Request) {
+		controller.ListKeys(res, req, keys, vars.Load())
 	})
 
-	http.HandleFunc("/wind/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetWind(res, req, &cache.WindCache, &vars)
+	http.HandleFunc("POST /admin/keys", func(res http.ResponseWriter, req *http.Request) {
+		controller.CreateKey(res, req, keys, vars.Load())
 	})
 
-	http.HandleFunc("/forecast/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetForecast(res, req, &cache.ForecastCache, &vars)
+	http.HandleFunc("DELETE /admin/keys/{id}", func(res http.ResponseWriter, req *http.Request) {
+		controller.DeleteKey(res, req, keys, vars.Load())
 	})
 
-	http.HandleFunc("/moon", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetMoon(res, req, &cache.MoonCache, &vars)
+	http.HandleFunc("GET /admin/quota", func(res http.ResponseWriter, req *http.Request) {
+		controller.AdminQuota(res, req, budget, vars.Load())
 	})
 
-	http.HandleFunc("/stats/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetStatistics(res, req, statDB)
+	handler := middleware.CORS(middleware.Routes(http.DefaultServeMux), http.DefaultServeMux, &cors)
+	handler = middleware.Compress(handler, middleware.CompressConfig{
+		Enabled: cfg.Compression.Enabled,
+		MinSize: cfg.Compression.MinSize,
 	})
+	handler = middleware.AccessLog(handler, http.DefaultServeMux, logger)
+	handler = middleware.Metrics(handler, http.DefaultServeMux)
+
+	// Upstream calls may be retried, hence the write timeout
+	// has to be longer than the upstream timeout
+	server := &http.Server{
+		Addr:              cfg.Listen.Address,
+		Handler:           middleware.RequestID(handler),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	// Serve HTTPS and HTTP/2, reading the certificate
+	// again whenever its files change on disk
+	if cfg.Listen.TLS.CertFile != "" {
+		certStore, err := types.InitCertStore(cfg.Listen.TLS.CertFile, cfg.Listen.TLS.KeyFile)
+		if err != nil {
+			log.Fatalf("Cannot load the TLS certificate: %v", err)
+		}
+
+		server.TLSConfig, err = newTLSConfig(&cfg.Listen.TLS, certStore)
+		if err != nil {
+			log.Fatalf("Cannot load the client certificate authorities: %v", err)
+		}
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+
+			ticker := time.NewTicker(configPollInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+
+				reloaded, err := certStore.Reload()
+				if err != nil {
+					slog.Error("Cannot reload the TLS certificate", slog.Any("error", err))
+				} else if reloaded {
+					slog.Info("TLS certificate reloaded")
+				}
+			}
+		}()
+	}
+
+	serverErr := make(chan error, 3)
+
+	// Serve the gRPC service alongside the HTTP server, sharing
+	// its TLS configuration, API keys and rate limits
+	var (
+		rpcServer  *grpc.Server
+		rpcService *controller.RPCServer
+	)
+	if cfg.Listen.GRPCAddress != "" {
+		unary, stream := middleware.RPCRateLimit(keys, limiter, &rateLimit)
+		options := []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(unary),
+			grpc.ChainStreamInterceptor(stream),
+		}
+		if server.TLSConfig != nil {
+			options = append(options, grpc.Creds(credentials.NewTLS(server.TLSConfig)))
+		}
+
+		rpcServer = grpc.NewServer(options...)
+		rpcService = controller.NewRPCServer(cache, statDB, &vars)
+		rpc.RegisterZephyrServer(rpcServer, rpcService)
+
+		listener, err := net.Listen("tcp", cfg.Listen.GRPCAddress)
+		if err != nil {
+			log.Fatalf("Cannot start the gRPC server: %v", err)
+		}
+
+		go func() {
+			slog.Info("gRPC server listening", slog.String("address", cfg.Listen.GRPCAddress), slog.Bool("tls", server.TLSConfig != nil))
+			serverErr <- rpcServer.Serve(listener)
+		}()
+	}
+
+	// Serve the liveness endpoint over plain HTTP, so that local probes(e.g.
+	// the Docker HEALTHCHECK) work even when mutual TLS is required
+	var healthServer *http.Server
+	if cfg.Listen.HealthAddress != "" {
+		healthMux := http.NewServeMux()
+		healthMux.HandleFunc("GET /healthz", controller.GetHealth)
+
+		healthServer = &http.Server{
+			Addr:              cfg.Listen.HealthAddress,
+			Handler:           healthMux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		go func() {
+			slog.Info("Health server listening", slog.String("address", healthServer.Addr))
+			serverErr <- healthServer.ListenAndServe()
+		}()
+	}
+
+	go func() {
+		slog.Info("Server listening", slog.String("address", server.Addr), slog.Bool("tls", server.TLSConfig != nil))
+		if server.TLSConfig != nil {
+			// The certificate is provided by the TLS configuration
+			serverErr <- server.ListenAndServeTLS("", "")
+		} else {
+			serverErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
+		log.Fatalf("Cannot start the server: %v", err)
+	case <-ctx.Done():
+	}
+
+	// Stop accepting new connections and wait for in-flight requests.
+	// A second signal terminates the process immediately
+	stop()
+	slog.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Cannot drain connections", slog.Any("error", err))
+	}
+
+	if healthServer != nil {
+		healthServer.Shutdown(shutdownCtx)
+	}
+
+	if rpcServer != nil {
+		rpcService.Close()
+		rpcServer.GracefulStop()
+	}
+
+	// Stop background workers and flush the state to disk
+	workers.Wait()
+
+	if err := cache.SaveSnapshot(cacheSnapshot); err != nil {
+		slog.Error("Cannot save the cache snapshot", slog.Any("error", err))
+	}
+	if err := statDB.SaveSnapshot(statDBSnapshot); err != nil {
+		slog.Error("Cannot save the statistical database snapshot", slog.Any("error", err))
+	}
+	if err := budget.Save(); err != nil {
+		slog.Error("Cannot save the budget file", slog.Any("error", err))
+	}
 
-	listenAddr := fmt.Sprintf(":%s", port)
-	log.Printf("Server listening on %s", listenAddr)
-	http.ListenAndServe(listenAddr, nil)
+	slog.Info("Server stopped")
 }
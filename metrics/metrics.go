@@ -0,0 +1,251 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default histogram buckets, expressed in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector, representing a metric family that can be
+// exposed using the Prometheus text format
+type Collector interface {
+	Collect(w io.Writer)
+}
+
+// Registry, representing a set of collectors
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (registry *Registry) Register(collectors ...Collector) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.collectors = append(registry.collectors, collectors...)
+}
+
+// Write exposes every registered collector using the Prometheus text format
+func (registry *Registry) Write(w io.Writer) {
+	registry.mu.Lock()
+	collectors := append([]Collector(nil), registry.collectors...)
+	registry.mu.Unlock()
+
+	for _, collector := range collectors {
+		collector.Collect(w)
+	}
+}
+
+// Sample, representing a single value of a metric family
+type Sample struct {
+	Labels []string // Label values, in the same order of the label names
+	Value  float64
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func fmtFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// fmtLabels formats a set of label pairs(e.g. '{route="/moon",status="200"}')
+func fmtLabels(names []string, values []string, extra ...string) string {
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for idx, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[idx])))
+	}
+	for idx := 0; idx+1 < len(extra); idx += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[idx], escapeLabel(extra[idx+1])))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func writeHeader(w io.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// Join label values into a single map key
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// CounterVec, representing a monotonic counter partitioned by labels
+type CounterVec struct {
+	mu     sync.Mutex
+	name   string
+	help   string
+	labels []string
+	series map[string]*Sample
+}
+
+func NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*Sample),
+	}
+}
+
+func (counter *CounterVec) Add(value float64, labelValues ...string) {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	key := seriesKey(labelValues)
+	val, isPresent := counter.series[key]
+	if !isPresent {
+		val = &Sample{Labels: append([]string(nil), labelValues...)}
+		counter.series[key] = val
+	}
+
+	val.Value += value
+}
+
+func (counter *CounterVec) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+func (counter *CounterVec) Collect(w io.Writer) {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	writeHeader(w, counter.name, counter.help, "counter")
+	for _, key := range sortedKeys(counter.series) {
+		val := counter.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", counter.name, fmtLabels(counter.labels, val.Labels), fmtFloat(val.Value))
+	}
+}
+
+// Structure representing the observations of a single histogram
+type histogram struct {
+	labels []string
+	counts []uint64 // Non-cumulative count of each bucket
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec, representing a histogram partitioned by labels
+type HistogramVec struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogram
+}
+
+func NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogram),
+	}
+}
+
+func (hist *HistogramVec) Observe(value float64, labelValues ...string) {
+	hist.mu.Lock()
+	defer hist.mu.Unlock()
+
+	key := seriesKey(labelValues)
+	val, isPresent := hist.series[key]
+	if !isPresent {
+		val = &histogram{
+			labels: append([]string(nil), labelValues...),
+			counts: make([]uint64, len(hist.buckets)),
+		}
+		hist.series[key] = val
+	}
+
+	// Observations greater than the last bucket are only counted by '+Inf'
+	if idx := sort.SearchFloat64s(hist.buckets, value); idx < len(hist.buckets) {
+		val.counts[idx]++
+	}
+	val.sum += value
+	val.count++
+}
+
+func (hist *HistogramVec) Collect(w io.Writer) {
+	hist.mu.Lock()
+	defer hist.mu.Unlock()
+
+	writeHeader(w, hist.name, hist.help, "histogram")
+	for _, key := range sortedKeys(hist.series) {
+		val := hist.series[key]
+
+		var cumulative uint64
+		for idx, bound := range hist.buckets {
+			cumulative += val.counts[idx]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", hist.name, fmtLabels(hist.labels, val.labels, "le", fmtFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", hist.name, fmtLabels(hist.labels, val.labels, "le", "+Inf"), val.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", hist.name, fmtLabels(hist.labels, val.labels), fmtFloat(val.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", hist.name, fmtLabels(hist.labels, val.labels), val.count)
+	}
+}
+
+// Func, representing a metric family whose samples are
+// computed at scrape time(e.g. from an existing data structure)
+type Func struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	collect func() []Sample
+}
+
+func NewGaugeFunc(name string, help string, collect func() []Sample, labels ...string) *Func {
+	return &Func{name: name, help: help, kind: "gauge", labels: labels, collect: collect}
+}
+
+func NewCounterFunc(name string, help string, collect func() []Sample, labels ...string) *Func {
+	return &Func{name: name, help: help, kind: "counter", labels: labels, collect: collect}
+}
+
+func (fn *Func) Collect(w io.Writer) {
+	samples := fn.collect()
+	sort.Slice(samples, func(i, j int) bool {
+		return seriesKey(samples[i].Labels) < seriesKey(samples[j].Labels)
+	})
+
+	writeHeader(w, fn.name, fn.help, fn.kind)
+	for _, val := range samples {
+		fmt.Fprintf(w, "%s%s %s\n", fn.name, fmtLabels(fn.labels, val.Labels), fmtFloat(val.Value))
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	counter := NewCounterVec("requests_total", "Number of requests.", "route", "status")
+	counter.Inc("/moon", "200")
+	counter.Add(2, "/weather/", "200")
+
+	hist := NewHistogramVec("latency_seconds", "Latency of requests.", []float64{0.1, 1}, "route")
+	hist.Observe(0.05, "/moon")
+	hist.Observe(0.5, "/moon")
+	hist.Observe(3, "/moon")
+
+	gauge := NewGaugeFunc("quota_remaining", "Calls left.", func() []Sample {
+		return []Sample{{Value: 42}}
+	})
+
+	registry := NewRegistry()
+	registry.Register(counter, hist, gauge)
+
+	var buf strings.Builder
+	registry.Write(&buf)
+	got := buf.String()
+
+	tests := []string{
+		"# TYPE requests_total counter\n",
+		`requests_total{route="/moon",status="200"} 1` + "\n",
+		`requests_total{route="/weather/",status="200"} 2` + "\n",
+		"# TYPE latency_seconds histogram\n",
+		`latency_seconds_bucket{route="/moon",le="0.1"} 1` + "\n",
+		`latency_seconds_bucket{route="/moon",le="1"} 2` + "\n",
+		`latency_seconds_bucket{route="/moon",le="+Inf"} 3` + "\n",
+		`latency_seconds_sum{route="/moon"} 3.55` + "\n",
+		`latency_seconds_count{route="/moon"} 3` + "\n",
+		"# TYPE quota_remaining gauge\nquota_remaining 42\n",
+	}
+
+	for _, expected := range tests {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Got %q, wanted it to contain %q", got, expected)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	got := fmtLabels([]string{"city"}, []string{"a\"b\\c\nd"})
+	expected := `{city="a\"b\\c\nd"}`
+
+	if got != expected {
+		t.Errorf("Got %s, wanted %s", got, expected)
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+// Registry and metric families of the service
+var (
+	Default = NewRegistry()
+
+	HTTPRequests = NewCounterVec(
+		"zephyr_http_requests_total",
+		"Number of HTTP requests by route and status code.",
+		"route", "status",
+	)
+	HTTPDuration = NewHistogramVec(
+		"zephyr_http_request_duration_seconds",
+		"Latency of HTTP requests by route and status code.",
+		DefaultBuckets, "route", "status",
+	)
+	UpstreamRequests = NewCounterVec(
+		"zephyr_upstream_requests_total",
+		"Number of upstream calls by endpoint and outcome.",
+		"endpoint", "outcome",
+	)
+	UpstreamDuration = NewHistogramVec(
+		"zephyr_upstream_request_duration_seconds",
+		"Latency of upstream calls by endpoint.",
+		DefaultBuckets, "endpoint",
+	)
+)
+
+func init() {
+	Default.Register(HTTPRequests, HTTPDuration, UpstreamRequests, UpstreamDuration)
+}
@@ -0,0 +1,258 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressConfig, representing the settings of response compression
+type CompressConfig struct {
+	Enabled bool
+	MinSize int // Smaller responses are sent uncompressed
+}
+
+// Media types worth compressing
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"text/",
+}
+
+var gzipPool = sync.Pool{
+	New: func() any {
+		writer, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return writer
+	},
+}
+
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acceptsGzip reports whether the 'Accept-Encoding' header of a request
+// allows gzip, honouring quality values(e.g. 'gzip;q=0')
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, item := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			quality, _ = strconv.ParseFloat(value, 64)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip":
+			// An explicit entry takes precedence over the wildcard
+			return quality > 0
+		case "*":
+			accepted = quality > 0
+		}
+	}
+
+	return accepted
+}
+
+// compressWriter buffers the beginning of a response until it either
+// reaches the size threshold or ends, and then decides whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	buf         []byte
+	gzipWriter  *gzip.Writer
+	decided     bool
+	etagSuffix  bool // Whether the client validated a compressed representation
+	isHeadReply bool
+}
+
+const gzipETagSuffix = "-gzip"
+
+func (writer *compressWriter) WriteHeader(status int) {
+	if writer.decided {
+		writer.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	// Informational responses do not carry a body
+	if status >= 100 && status < 200 {
+		writer.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if writer.status == 0 {
+		writer.status = status
+	}
+}
+
+func (writer *compressWriter) Write(buf []byte) (int, error) {
+	if writer.status == 0 {
+		writer.status = http.StatusOK
+	}
+
+	if writer.decided {
+		if writer.gzipWriter != nil {
+			return writer.gzipWriter.Write(buf)
+		}
+
+		return writer.ResponseWriter.Write(buf)
+	}
+
+	writer.buf = append(writer.buf, buf...)
+	if len(writer.buf) >= writer.minSize {
+		if err := writer.decide(false); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(buf), nil
+}
+
+// decide writes the header and the buffered body, compressing them when the
+// response is compressible and either large enough or being streamed
+func (writer *compressWriter) decide(isStreaming bool) error {
+	writer.decided = true
+	if writer.status == 0 {
+		writer.status = http.StatusOK
+	}
+
+	header := writer.Header()
+	compressible := isCompressible(header.Get("Content-Type"))
+
+	// Revalidated compressed representations keep their own ETag
+	if writer.status == http.StatusNotModified && writer.etagSuffix {
+		writer.renameETag()
+	}
+
+	// HEAD replies carry no body but advertise the length of the GET one,
+	// so that both get the same content coding and ETag
+	size := len(writer.buf)
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && writer.isHeadReply {
+		size = max(size, length)
+	}
+
+	isCompressed := compressible &&
+		(isStreaming || size >= writer.minSize) &&
+		header.Get("Content-Encoding") == "" &&
+		writer.status != http.StatusNoContent &&
+		writer.status != http.StatusNotModified &&
+		writer.status != http.StatusPartialContent
+
+	if isCompressed {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		writer.renameETag()
+
+		if !writer.isHeadReply {
+			writer.gzipWriter = gzipPool.Get().(*gzip.Writer)
+			writer.gzipWriter.Reset(writer.ResponseWriter)
+		}
+	}
+
+	writer.ResponseWriter.WriteHeader(writer.status)
+	if len(writer.buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if writer.gzipWriter != nil {
+		_, err = writer.gzipWriter.Write(writer.buf)
+	} else {
+		_, err = writer.ResponseWriter.Write(writer.buf)
+	}
+	writer.buf = nil
+
+	return err
+}
+
+// renameETag distinguishes the ETag of compressed representations,
+// since strong validators must differ between encodings
+func (writer *compressWriter) renameETag() {
+	etag := writer.Header().Get("ETag")
+	if etag == "" || strings.HasSuffix(etag, gzipETagSuffix+`"`) {
+		return
+	}
+
+	if trimmed, found := strings.CutSuffix(etag, `"`); found {
+		writer.Header().Set("ETag", trimmed+gzipETagSuffix+`"`)
+	}
+}
+
+// Flush sends the buffered data to the client(e.g. server-sent events)
+func (writer *compressWriter) Flush() {
+	if !writer.decided {
+		writer.decide(true)
+	}
+
+	if writer.gzipWriter != nil {
+		writer.gzipWriter.Flush()
+	}
+
+	http.NewResponseController(writer.ResponseWriter).Flush()
+}
+
+func (writer *compressWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+func (writer *compressWriter) close() {
+	// HEAD handlers may return without writing anything
+	if !writer.decided && (writer.status != 0 || writer.isHeadReply) {
+		writer.decide(false)
+	}
+
+	if writer.gzipWriter != nil {
+		writer.gzipWriter.Close()
+		gzipPool.Put(writer.gzipWriter)
+		writer.gzipWriter = nil
+	}
+}
+
+// Compress compresses JSON, text and event stream responses through gzip
+// when clients accept it and responses are larger than the threshold
+func Compress(next http.Handler, config CompressConfig) http.Handler {
+	if !config.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// Responses depend on the header even when sent uncompressed
+		res.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(req.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(res, req)
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: res,
+			minSize:        config.MinSize,
+			isHeadReply:    req.Method == http.MethodHead,
+		}
+		defer writer.close()
+
+		// Validate compressed representations against the ETag
+		// computed by the handlers over the uncompressed body
+		if ifNoneMatch := req.Header.Get("If-None-Match"); strings.Contains(ifNoneMatch, gzipETagSuffix+`"`) {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", strings.ReplaceAll(ifNoneMatch, gzipETagSuffix+`"`, `"`))
+			writer.etagSuffix = true
+		}
+
+		next.ServeHTTP(writer, req)
+	})
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		Header   string
+		Expected bool
+	}{
+		{"gzip, deflate, br", true},
+		{"br;q=1.0, gzip;q=0.8", true},
+		{"gzip;q=0", false},
+		{"*", true},
+		{"*;q=0.5, gzip;q=0", false},
+		{"identity", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Header, func(t *testing.T) {
+			if got := acceptsGzip(test.Header); got != test.Expected {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	largeBody := `{"forecast":"` + strings.Repeat("sunny ", 500) + `"}`
+	handler := Compress(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/large":
+			res.Header().Set("Content-Type", "application/json")
+			res.Header().Set("ETag", `"abc"`)
+			res.Header().Set("Content-Length", strconv.Itoa(len(largeBody)))
+			if req.Method != http.MethodHead {
+				io.WriteString(res, largeBody)
+			}
+		case "/small":
+			res.Header().Set("Content-Type", "application/json")
+			io.WriteString(res, `{}`)
+		case "/binary":
+			res.Header().Set("Content-Type", "image/png")
+			io.WriteString(res, largeBody)
+		}
+	}), CompressConfig{Enabled: true, MinSize: 1024})
+
+	tests := []struct {
+		Name           string
+		Method         string
+		Path           string
+		AcceptEncoding string
+		Encoding       string
+		ETag           string
+	}{
+		{"Large JSON", http.MethodGet, "/large", "gzip", "gzip", `"abc-gzip"`},
+		{"HEAD of large JSON", http.MethodHead, "/large", "gzip", "gzip", `"abc-gzip"`},
+		{"Gzip not accepted", http.MethodGet, "/large", "", "", `"abc"`},
+		{"HEAD without gzip", http.MethodHead, "/large", "", "", `"abc"`},
+		{"Below threshold", http.MethodGet, "/small", "gzip", "", ""},
+		{"Not compressible", http.MethodGet, "/binary", "gzip", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(test.Method, test.Path, nil)
+			req.Header.Set("Accept-Encoding", test.AcceptEncoding)
+
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			if got := res.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Got Vary %q, wanted Accept-Encoding", got)
+			}
+
+			if got := res.Header().Get("Content-Encoding"); got != test.Encoding {
+				t.Fatalf("Got encoding %q, wanted %q", got, test.Encoding)
+			}
+
+			if got := res.Header().Get("ETag"); got != test.ETag {
+				t.Errorf("Got ETag %q, wanted %q", got, test.ETag)
+			}
+
+			if test.Method == http.MethodHead {
+				if res.Body.Len() != 0 {
+					t.Errorf("Got %d bytes, wanted an empty body", res.Body.Len())
+				}
+				return
+			}
+
+			body := io.Reader(res.Body)
+			if test.Encoding == "gzip" {
+				reader, err := gzip.NewReader(res.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = reader
+			}
+
+			content, _ := io.ReadAll(body)
+			if test.Path == "/large" && string(content) != largeBody {
+				t.Errorf("Got %d bytes, wanted the original body", len(content))
+			}
+		})
+	}
+}
+
+func TestCompressRevalidation(t *testing.T) {
+	handler := Compress(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("ETag", `"abc"`)
+		if req.Header.Get("If-None-Match") == `"abc"` {
+			res.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		res.Header().Set("Content-Type", "application/json")
+		io.WriteString(res, strings.Repeat(" ", 2048))
+	}), CompressConfig{Enabled: true, MinSize: 1024})
+
+	req := httptest.NewRequest(http.MethodGet, "/forecast/milan", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", `"abc-gzip"`)
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusNotModified {
+		t.Fatalf("Got %d, wanted %d", res.Code, http.StatusNotModified)
+	}
+
+	if got := res.Header().Get("ETag"); got != `"abc-gzip"` {
+		t.Errorf("Got %q, wanted \"abc-gzip\"", got)
+	}
+
+	if got := res.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Got Vary %q, wanted Accept-Encoding", got)
+	}
+}
+
+func TestCompressStreaming(t *testing.T) {
+	handler := Compress(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(res, "data: {}\n\n")
+		http.NewResponseController(res).Flush()
+	}), CompressConfig{Enabled: true, MinSize: 1024})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	// Streamed events are compressed regardless of the threshold
+	if got := res.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Got encoding %q, wanted gzip", got)
+	}
+
+	if !res.Flushed {
+		t.Errorf("Got an unflushed response, wanted a flushed one")
+	}
+}
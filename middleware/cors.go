@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CORSConfig, representing the origins allowed to call
+// the API from a browser(e.g. a standalone web app)
+type CORSConfig struct {
+	AllowedOrigins []string // '*' allows every origin
+	MaxAge         time.Duration
+}
+
+// Headers that browsers may send and read on cross-origin requests
+const (
+	corsAllowedHeaders = "Authorization, Content-Type, X-API-Key, X-Request-ID"
+	corsExposedHeaders = "Retry-After, X-Request-ID"
+)
+
+func (config *CORSConfig) isAllowed(origin string) bool {
+	return slices.Contains(config.AllowedOrigins, "*") || slices.Contains(config.AllowedOrigins, origin)
+}
+
+// CORS adds the Cross-Origin Resource Sharing headers to the responses
+// of allowed origins and answers their preflight requests. The settings
+// can be swapped at runtime(e.g. on configuration reloads)
+func CORS(next http.Handler, mux *http.ServeMux, settings *atomic.Pointer[CORSConfig]) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		config := settings.Load()
+		origin := req.Header.Get("Origin")
+
+		if len(config.AllowedOrigins) > 0 {
+			res.Header().Add("Vary", "Origin")
+		}
+
+		if origin == "" || !config.isAllowed(origin) {
+			next.ServeHTTP(res, req)
+			return
+		}
+
+		res.Header().Set("Access-Control-Allow-Origin", origin)
+		res.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		// Answer preflight requests without reaching the handlers
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			methods := allowedMethods(mux, req)
+			if len(methods) == 0 {
+				next.ServeHTTP(res, req)
+				return
+			}
+
+			res.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			res.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			if config.MaxAge > 0 {
+				res.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+			}
+
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
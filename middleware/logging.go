@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// AccessLog writes a structured log entry for each request, including the
+// details collected by the handlers(e.g. the requested city or the cache outcome)
+func AccessLog(next http.Handler, mux *http.ServeMux, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		route := "unmatched"
+		if _, pattern := mux.Handler(req); pattern != "" {
+			route = pattern
+		}
+
+		requestLog := &types.RequestLog{}
+		ctx := types.WithRequestLog(req.Context(), requestLog)
+
+		startTime := time.Now()
+		rec := &responseRecorder{ResponseWriter: res}
+		next.ServeHTTP(rec, req.WithContext(ctx))
+
+		// The query string is not logged since it may contain API keys
+		attrs := []slog.Attr{
+			slog.String("request_id", types.RequestID(ctx)),
+			slog.String("method", req.Method),
+			slog.String("route", route),
+			slog.String("path", req.URL.Path),
+			slog.Int("status", rec.Status()),
+			slog.Int("bytes", rec.bytes),
+			slog.Float64("duration_ms", float64(time.Since(startTime).Microseconds())/1000),
+			slog.String("remote", req.RemoteAddr),
+		}
+		attrs = append(attrs, requestLog.Attrs()...)
+
+		level := slog.LevelInfo
+		if rec.Status() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(ctx, level, "request", attrs...)
+	})
+}
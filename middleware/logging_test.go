@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestAccessLog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/weather/", func(res http.ResponseWriter, req *http.Request) {
+		requestLog := types.GetRequestLog(req.Context())
+		requestLog.SetCity("Milan,IT")
+		requestLog.SetCache("hit")
+
+		res.WriteHeader(http.StatusOK)
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := RequestID(AccessLog(mux, mux, logger))
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/milan?api_key=secret", nil)
+	req.Header.Set("X-Request-ID", "test-request")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Got %v, wanted a JSON log entry", err)
+	}
+
+	tests := []struct {
+		Field    string
+		Expected any
+	}{
+		{"request_id", "test-request"},
+		{"route", "/weather/"},
+		{"path", "/weather/milan"},
+		{"status", float64(http.StatusOK)},
+		{"city", "Milan,IT"},
+		{"cache", "hit"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Field, func(t *testing.T) {
+			if entry[test.Field] != test.Expected {
+				t.Errorf("Got %v, wanted %v", entry[test.Field], test.Expected)
+			}
+		})
+	}
+}
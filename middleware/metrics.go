@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ceticamarco/zephyr/metrics"
+)
+
+// Structure representing a response writer that records the status code
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(buf []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	written, err := rec.ResponseWriter.Write(buf)
+	rec.bytes += written
+
+	return written, err
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *responseRecorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+
+	return rec.status
+}
+
+// Metrics records the number and the latency of requests. Requests are
+// grouped by the mux pattern they match to keep the number of series bounded
+func Metrics(next http.Handler, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		route := "unmatched"
+		if _, pattern := mux.Handler(req); pattern != "" {
+			route = pattern
+		}
+
+		startTime := time.Now()
+		rec := &responseRecorder{ResponseWriter: res}
+		next.ServeHTTP(rec, req)
+
+		status := strconv.Itoa(rec.Status())
+		metrics.HTTPRequests.Inc(route, status)
+		metrics.HTTPDuration.Observe(time.Since(startTime).Seconds(), route, status)
+	})
+}
@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ceticamarco/zephyr/controller"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Structure representing a token bucket
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// Limiter, representing a set of token buckets indexed by client.
+// Each bucket holds up to 'burst' tokens and refills at 'rate'
+// tokens per minute; every request consumes a single token
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow consumes a token from the bucket of the given client, returning
+// how long the client has to wait when the bucket is empty
+func (limiter *Limiter) Allow(client string, rate uint, burst uint) (bool, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	currentTime := time.Now()
+	perSecond := float64(rate) / 60
+
+	val, isPresent := limiter.buckets[client]
+	if !isPresent {
+		val = &bucket{tokens: float64(burst), lastSeen: currentTime}
+		limiter.buckets[client] = val
+	}
+
+	// Refill the bucket according to the elapsed time
+	elapsed := currentTime.Sub(val.lastSeen).Seconds()
+	val.tokens = math.Min(float64(burst), val.tokens+elapsed*perSecond)
+	val.lastSeen = currentTime
+
+	if val.tokens >= 1 {
+		val.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - val.tokens) / perSecond * float64(time.Second))
+
+	return false, wait
+}
+
+// Sweep removes the buckets of clients that have been idle for the given amount of time
+func (limiter *Limiter) Sweep(idle time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	for client, val := range limiter.buckets {
+		if time.Since(val.lastSeen) > idle {
+			delete(limiter.buckets, client)
+		}
+	}
+}
+
+// RateLimitConfig, representing the settings of the rate limiting middleware
+type RateLimitConfig struct {
+	RequireKey bool // Refuse requests without an API key
+	TrustProxy bool // Read the client address from the last 'X-Forwarded-For' entry
+	IPRate     uint // Requests per minute of anonymous clients(0 disables the limit)
+	KeyRate    uint // Default requests per minute of API keys(0 disables the limit)
+}
+
+// clientIP returns the address of a client. Behind a trusted proxy, it is the
+// right-most entry of 'X-Forwarded-For', which is appended by the proxy itself,
+// while the entries on its left are sent by the client and can be spoofed
+func clientIP(req *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if values := req.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			forwarded := values[len(values)-1]
+			if idx := strings.LastIndex(forwarded, ","); idx >= 0 {
+				forwarded = forwarded[idx+1:]
+			}
+
+			if ip := strings.TrimSpace(forwarded); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+func apiKey(req *http.Request) string {
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	return req.URL.Query().Get("api_key")
+}
+
+// admit authenticates a client through its API key(if any) and consumes a
+// token from its bucket, returning the error to report when the client is
+// refused along with how long it has to wait when it is rate limited
+func admit(keys *types.KeyStore, limiter *Limiter, config *RateLimitConfig, secret string, address string) (*controller.APIError, time.Duration) {
+	var (
+		client string
+		rate   uint
+		burst  uint
+	)
+
+	if secret != "" {
+		key, isPresent := keys.Lookup(secret)
+		if !isPresent {
+			return &controller.APIError{
+				Status: http.StatusUnauthorized,
+				Code:   "invalid-api-key",
+				Title:  "Unauthorized",
+				Detail: "Invalid API key",
+			}, 0
+		}
+
+		client, rate, burst = "key:"+key.ID, config.KeyRate, key.Burst
+		if key.Rate != 0 {
+			rate = key.Rate
+		}
+	} else {
+		if config.RequireKey {
+			return &controller.APIError{
+				Status: http.StatusUnauthorized,
+				Code:   "missing-api-key",
+				Title:  "Unauthorized",
+				Detail: "This service requires an API key(either through the 'X-API-Key' header or the 'api_key' parameter)",
+			}, 0
+		}
+
+		client, rate = "ip:"+address, config.IPRate
+	}
+
+	// By default, the bucket can hold a whole minute of requests
+	if burst == 0 {
+		burst = rate
+	}
+
+	if rate != 0 {
+		isAllowed, wait := limiter.Allow(client, rate, burst)
+		if !isAllowed {
+			return &controller.APIError{
+				Status: http.StatusTooManyRequests,
+				Code:   "rate-limited",
+				Title:  "Too many requests",
+				Detail: "Rate limit exceeded, retry later",
+			}, wait
+		}
+	}
+
+	return nil, 0
+}
+
+// RateLimit authenticates clients through their API key(if any) and
+// limits the number of requests of each key and of each anonymous client.
+// The settings can be swapped at runtime(e.g. on configuration reloads)
+func RateLimit(next http.Handler, keys *types.KeyStore, limiter *Limiter, settings *atomic.Pointer[RateLimitConfig]) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		config := settings.Load()
+
+		apiErr, wait := admit(keys, limiter, config, apiKey(req), clientIP(req, config.TrustProxy))
+		if apiErr != nil {
+			if wait > 0 {
+				res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			}
+			controller.WriteProblem(res, req, apiErr)
+			return
+		}
+
+		next.ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestRateLimit(t *testing.T) {
+	keys, _ := types.InitKeyStore("")
+	_, secret, _ := keys.Create("clock", 60, 1)
+
+	var settings atomic.Pointer[RateLimitConfig]
+	settings.Store(&RateLimitConfig{IPRate: 60, KeyRate: 120})
+
+	handler := RateLimit(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}), keys, NewLimiter(), &settings)
+
+	tests := []struct {
+		Name     string
+		Key      string
+		Expected int
+	}{
+		{"Anonymous client", "", http.StatusOK},
+		{"Valid key", secret, http.StatusOK},
+		{"Key bucket exhausted", secret, http.StatusTooManyRequests},
+		{"Invalid key", "zk_invalid", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather/milan", nil)
+			if test.Key != "" {
+				req.Header.Set("X-API-Key", test.Key)
+			}
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if res.Code != test.Expected {
+				t.Errorf("Got %d, wanted %d", res.Code, test.Expected)
+			}
+
+			if res.Code == http.StatusTooManyRequests && res.Header().Get("Retry-After") != "1" {
+				t.Errorf("Got Retry-After %q, wanted 1", res.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestRequireKey(t *testing.T) {
+	keys, _ := types.InitKeyStore("")
+	var settings atomic.Pointer[RateLimitConfig]
+	settings.Store(&RateLimitConfig{RequireKey: true})
+
+	handler := RateLimit(http.NotFoundHandler(), keys, NewLimiter(), &settings)
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/weather/milan", nil))
+
+	if res.Code != http.StatusUnauthorized {
+		t.Errorf("Got %d, wanted %d", res.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Forwarded  []string
+		TrustProxy bool
+		Expected   string
+	}{
+		{"Direct client", nil, false, "192.0.2.1"},
+		{"Ignored header", []string{"203.0.113.7"}, false, "192.0.2.1"},
+		{"Proxied client", []string{"203.0.113.7"}, true, "203.0.113.7"},
+		{"Spoofed entry", []string{"198.51.100.99, 203.0.113.7"}, true, "203.0.113.7"},
+		{"Repeated header", []string{"198.51.100.99", "203.0.113.7"}, true, "203.0.113.7"},
+		{"Empty header", []string{""}, true, "192.0.2.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather/milan", nil)
+			for _, val := range test.Forwarded {
+				req.Header.Add("X-Forwarded-For", val)
+			}
+
+			if got := clientIP(req, test.TrustProxy); got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+
+	// Spoofed addresses do not grant a fresh bucket to the same client
+	var settings atomic.Pointer[RateLimitConfig]
+	settings.Store(&RateLimitConfig{IPRate: 1, TrustProxy: true})
+
+	keys, _ := types.InitKeyStore("")
+	handler := RateLimit(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}), keys, NewLimiter(), &settings)
+
+	var codes []int
+	for _, spoofed := range []string{"198.51.100.1", "198.51.100.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/weather/milan", nil)
+		req.Header.Set("X-Forwarded-For", spoofed+", 203.0.113.7")
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+		codes = append(codes, res.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Got %v, wanted the second request to be rate limited", codes)
+	}
+}
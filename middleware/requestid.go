@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+const maxRequestIDLength = 64
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	// Only accept printable, non-whitespace ASCII characters
+	for _, ch := range requestID {
+		if ch <= ' ' || ch > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// RequestID tags each request with a unique identifier, reusing the
+// one provided by the client(or by a reverse proxy) when valid
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get("X-Request-ID")
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		res.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(res, req.WithContext(types.WithRequestID(req.Context(), requestID)))
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/controller"
+)
+
+// Methods probed when looking for the methods supported by a path
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// allowedMethods returns the methods the mux can route for the path of a request
+func allowedMethods(mux *http.ServeMux, req *http.Request) []string {
+	var methods []string
+	for _, method := range routeMethods {
+		probe := *req
+		probe.Method = method
+		if _, pattern := mux.Handler(&probe); pattern != "" {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// Routes answers OPTIONS requests with the methods supported by a path
+// and reports unknown paths and unsupported methods as problem details
+func Routes(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, pattern := mux.Handler(req); pattern != "" && req.Method != http.MethodOptions {
+			mux.ServeHTTP(res, req)
+			return
+		}
+
+		methods := allowedMethods(mux, req)
+		if len(methods) == 0 {
+			controller.WriteProblem(res, req, &controller.APIError{
+				Status: http.StatusNotFound,
+				Code:   "not-found",
+				Title:  "Not found",
+				Detail: "Unknown endpoint",
+			})
+			return
+		}
+
+		methods = append(methods, http.MethodOptions)
+		res.Header().Set("Allow", strings.Join(methods, ", "))
+
+		if req.Method == http.MethodOptions {
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		controller.WriteProblem(res, req, &controller.APIError{
+			Status: http.StatusMethodNotAllowed,
+			Code:   "method-not-allowed",
+			Title:  "Method not allowed",
+			Detail: "This endpoint only supports the " + strings.Join(methods, ", ") + " methods",
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /weather/{city...}", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("DELETE /admin/cache/{kind}", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func TestRoutes(t *testing.T) {
+	handler := Routes(newTestMux())
+
+	tests := []struct {
+		Name     string
+		Method   string
+		Path     string
+		Expected int
+		Allow    string
+	}{
+		{"Matching route", http.MethodGet, "/weather/milan", http.StatusOK, ""},
+		{"HEAD request", http.MethodHead, "/weather/milan", http.StatusOK, ""},
+		{"OPTIONS request", http.MethodOptions, "/weather/milan", http.StatusNoContent, "GET, HEAD, OPTIONS"},
+		{"Unsupported method", http.MethodPost, "/weather/milan", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS"},
+		{"Delete route", http.MethodGet, "/admin/cache/wind", http.StatusMethodNotAllowed, "DELETE, OPTIONS"},
+		{"Unknown path", http.MethodGet, "/unknown", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, httptest.NewRequest(test.Method, test.Path, nil))
+
+			if res.Code != test.Expected {
+				t.Errorf("Got %d, wanted %d", res.Code, test.Expected)
+			}
+
+			if got := res.Header().Get("Allow"); got != test.Allow {
+				t.Errorf("Got %q, wanted %q", got, test.Allow)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	mux := newTestMux()
+
+	var settings atomic.Pointer[CORSConfig]
+	settings.Store(&CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	handler := CORS(Routes(mux), mux, &settings)
+
+	tests := []struct {
+		Name     string
+		Method   string
+		Origin   string
+		Expected int
+		Allowed  string
+		Methods  string
+	}{
+		{"Allowed origin", http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com", ""},
+		{"Unknown origin", http.MethodGet, "https://evil.example.com", http.StatusOK, "", ""},
+		{"Preflight", http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "GET, HEAD"},
+		{"Unknown origin preflight", http.MethodOptions, "https://evil.example.com", http.StatusNoContent, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(test.Method, "/weather/milan", nil)
+			req.Header.Set("Origin", test.Origin)
+			if test.Method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			if res.Code != test.Expected {
+				t.Errorf("Got %d, wanted %d", res.Code, test.Expected)
+			}
+
+			if got := res.Header().Get("Access-Control-Allow-Origin"); got != test.Allowed {
+				t.Errorf("Got %q, wanted %q", got, test.Allowed)
+			}
+
+			if got := res.Header().Get("Access-Control-Allow-Methods"); got != test.Methods {
+				t.Errorf("Got %q, wanted %q", got, test.Methods)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/ceticamarco/zephyr/controller"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// rpcAdmit applies the rate limits to a gRPC call, reading the API key
+// from the 'x-api-key' metadata and the client address from the connection
+func rpcAdmit(ctx context.Context, keys *types.KeyStore, limiter *Limiter, settings *atomic.Pointer[RateLimitConfig]) error {
+	var secret string
+	if values := metadata.ValueFromIncomingContext(ctx, "x-api-key"); len(values) > 0 {
+		secret = values[0]
+	}
+
+	var address string
+	if client, isPresent := peer.FromContext(ctx); isPresent {
+		address = client.Addr.String()
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+	}
+
+	if apiErr, _ := admit(keys, limiter, settings.Load(), secret, address); apiErr != nil {
+		return controller.WriteRPCError(apiErr)
+	}
+
+	return nil
+}
+
+// RPCRateLimit applies the API keys and the rate limits of the HTTP API
+// to the gRPC service. A stream consumes a single token when it is opened
+func RPCRateLimit(keys *types.KeyStore, limiter *Limiter, settings *atomic.Pointer[RateLimitConfig]) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := rpcAdmit(ctx, keys, limiter, settings); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rpcAdmit(stream.Context(), keys, limiter, settings); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+
+	return unary, stream
+}
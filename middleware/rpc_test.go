@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ceticamarco/zephyr/rpc"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// testService answers every call without reaching the caches
+type testService struct {
+	rpc.UnimplementedZephyrServer
+}
+
+func (testService) GetMoon(context.Context, *rpc.MoonRequest) (*rpc.Moon, error) {
+	return &rpc.Moon{Phase: "Full Moon"}, nil
+}
+
+func (testService) WatchWeather(req *rpc.LocationRequest, stream grpc.ServerStreamingServer[rpc.Weather]) error {
+	return stream.Send(&rpc.Weather{Condition: "Clear"})
+}
+
+func TestRPCRateLimit(t *testing.T) {
+	keys, _ := types.InitKeyStore("")
+	_, secret, _ := keys.Create("clock", 60, 2)
+
+	var settings atomic.Pointer[RateLimitConfig]
+	settings.Store(&RateLimitConfig{RequireKey: true})
+
+	unary, stream := RPCRateLimit(keys, NewLimiter(), &settings)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream))
+	rpc.RegisterZephyrServer(server, testService{})
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	client := rpc.NewZephyrClient(conn)
+
+	tests := []struct {
+		Name     string
+		Key      string
+		Expected codes.Code
+	}{
+		{"Missing key", "", codes.Unauthenticated},
+		{"Invalid key", "zk_invalid", codes.Unauthenticated},
+		{"Valid key", secret, codes.OK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.Key != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", test.Key)
+			}
+
+			if _, err := client.GetMoon(ctx, &rpc.MoonRequest{}); status.Code(err) != test.Expected {
+				t.Errorf("Got %v from the unary call, wanted %s", err, test.Expected)
+			}
+
+			// Streams are refused before the first message
+			watch, err := client.WatchWeather(ctx, &rpc.LocationRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := watch.Recv(); status.Code(err) != test.Expected {
+				t.Errorf("Got %v from the stream, wanted %s", err, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("Upstream provider temporarily disabled after repeated failures")
+
+// BreakerState, representing the state of a circuit breaker
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (state BreakerState) String() string {
+	switch state {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerStatus, representing a snapshot of a circuit breaker
+type BreakerStatus struct {
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`
+	OpenedAt *time.Time `json:"openedAt,omitempty"`
+}
+
+// Breaker, representing a circuit breaker that stops calling an upstream
+// endpoint after a number of consecutive failures. Once the cooldown period
+// expires, a single probe call is allowed: if it succeeds the circuit is closed,
+// otherwise it is opened again
+type Breaker struct {
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	Threshold int
+	Cooldown  time.Duration
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+	}
+}
+
+// Configure changes the settings of the breaker without resetting its state
+func (breaker *Breaker) Configure(threshold int, cooldown time.Duration) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.Threshold = threshold
+	breaker.Cooldown = cooldown
+}
+
+// Allow reports whether a call to the upstream endpoint is allowed
+func (breaker *Breaker) Allow() bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.state {
+	case StateOpen:
+		if time.Since(breaker.openedAt) < breaker.Cooldown {
+			return false
+		}
+
+		// Cooldown expired, allow a single probe call
+		breaker.state = StateHalfOpen
+		breaker.probing = true
+
+		return true
+	case StateHalfOpen:
+		if breaker.probing {
+			return false
+		}
+		breaker.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// Record updates the breaker with the outcome of an upstream call
+func (breaker *Breaker) Record(success bool) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+
+	if success {
+		breaker.state = StateClosed
+		breaker.failures = 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.state == StateHalfOpen || breaker.failures >= breaker.Threshold {
+		breaker.state = StateOpen
+		breaker.openedAt = time.Now()
+	}
+}
+
+// Release allows a new probe call without recording any outcome, for
+// calls that have not reached the upstream endpoint(e.g. canceled requests)
+func (breaker *Breaker) Release() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+}
+
+func (breaker *Breaker) Status() BreakerStatus {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	status := BreakerStatus{
+		State:    breaker.state.String(),
+		Failures: breaker.failures,
+	}
+
+	if breaker.state != StateClosed {
+		openedAt := breaker.openedAt
+		status.OpenedAt = &openedAt
+	}
+
+	return status
+}
+
+// isFailure reports whether an error means that the upstream endpoint is unhealthy.
+// Client errors(e.g. unknown locations) do not trip the breaker
+func isFailure(err error) bool {
+	return errors.Is(err, ErrUpstreamDown) ||
+		errors.Is(err, ErrTimeout) ||
+		errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrInvalidResponse)
+}
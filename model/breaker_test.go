@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	breaker := NewBreaker(2, 10*time.Millisecond)
+
+	tests := []struct {
+		Name     string
+		Wait     time.Duration
+		Success  bool
+		Expected BreakerState
+	}{
+		{"First failure", 0, false, StateClosed},
+		{"Threshold reached", 0, false, StateOpen},
+		{"Failed probe", 20 * time.Millisecond, false, StateOpen},
+		{"Successful probe", 20 * time.Millisecond, true, StateClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			time.Sleep(test.Wait)
+
+			if !breaker.Allow() {
+				t.Fatalf("Call refused, wanted allowed")
+			}
+			breaker.Record(test.Success)
+
+			if breaker.state != test.Expected {
+				t.Errorf("Got %s, wanted %s", breaker.state, test.Expected)
+			}
+		})
+	}
+}
+
+func TestBreakerFailFast(t *testing.T) {
+	breaker := NewBreaker(1, time.Hour)
+	breaker.Allow()
+	breaker.Record(false)
+
+	if breaker.Allow() {
+		t.Errorf("Call allowed, wanted refused")
+	}
+}
+
+func TestBreakerSingleProbe(t *testing.T) {
+	breaker := NewBreaker(1, time.Millisecond)
+	breaker.Allow()
+	breaker.Record(false)
+	time.Sleep(5 * time.Millisecond)
+
+	// Only a single probe is allowed while half-open
+	if !breaker.Allow() || breaker.Allow() {
+		t.Errorf("Wanted a single probe call")
+	}
+}
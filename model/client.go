@@ -0,0 +1,274 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ceticamarco/zephyr/metrics"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Errors returned by the upstream provider
+var (
+	ErrUnauthorized    = errors.New("Upstream provider rejected the API key")
+	ErrRateLimited     = errors.New("Upstream provider rate limit exceeded")
+	ErrNotFound        = errors.New("Upstream provider resource not found")
+	ErrBadRequest      = errors.New("Upstream provider rejected the request")
+	ErrUpstreamDown    = errors.New("Upstream provider unavailable")
+	ErrTimeout         = errors.New("Upstream provider timed out")
+	ErrInvalidResponse = errors.New("Upstream provider returned an invalid response")
+	ErrQuotaExhausted  = errors.New("Daily upstream quota exhausted")
+)
+
+// UpstreamError, representing a failed call to the upstream provider
+type UpstreamError struct {
+	Path       string
+	StatusCode int
+	Err        error
+}
+
+func (err *UpstreamError) Error() string {
+	if err.StatusCode != 0 {
+		return fmt.Sprintf("%s (%s: HTTP %d)", err.Err.Error(), err.Path, err.StatusCode)
+	}
+
+	return fmt.Sprintf("%s (%s)", err.Err.Error(), err.Path)
+}
+
+func (err *UpstreamError) Unwrap() error {
+	return err.Err
+}
+
+// Client, representing an HTTP client to the upstream provider
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	Timeout    time.Duration // Deadline of a single attempt
+	MaxRetries int
+	Backoff    time.Duration // Base delay between two attempts
+	MaxBackoff time.Duration
+	Budget     *types.Budget // Daily quota, if any
+	Breakers   map[string]*Breaker
+}
+
+// Upstream, representing the client used by every model function
+var Upstream = NewClient()
+
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{},
+		BaseURL:    BASE_URL,
+		Timeout:    5 * time.Second,
+		MaxRetries: 2,
+		Backoff:    250 * time.Millisecond,
+		MaxBackoff: 2 * time.Second,
+		Breakers: map[string]*Breaker{
+			ENDPOINT_GEOCODING: NewBreaker(5, 30*time.Second),
+			ENDPOINT_ONECALL:   NewBreaker(5, 30*time.Second),
+		},
+	}
+}
+
+// BreakerStatus returns the state of the circuit breaker of every endpoint type
+func (client *Client) BreakerStatus() map[string]BreakerStatus {
+	status := make(map[string]BreakerStatus, len(client.Breakers))
+	for endpoint, breaker := range client.Breakers {
+		status[endpoint] = breaker.Status()
+	}
+
+	return status
+}
+
+// IsThrottled reports whether the daily quota is running low,
+// in which case callers should prefer stale data over upstream calls
+func (client *Client) IsThrottled() bool {
+	return client.Budget != nil && client.Budget.IsThrottled()
+}
+
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrUpstreamDown) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout)
+}
+
+func (client *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	// Honor the 'Retry-After' header when it is shorter than the maximum delay
+	if retryAfter > 0 && retryAfter <= client.MaxBackoff {
+		return retryAfter
+	}
+
+	// Otherwise compute an exponential backoff with full jitter
+	delay := min(client.Backoff<<attempt, client.MaxBackoff)
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (client *Client) fetch(ctx context.Context, path string, params url.Values, out any) error {
+	breaker, hasBreaker := client.Breakers[endpointType(path)]
+	if !hasBreaker {
+		return client.retry(ctx, path, params, out)
+	}
+
+	// Fail fast while the upstream endpoint is unhealthy
+	if !breaker.Allow() {
+		return &UpstreamError{Path: path, Err: ErrCircuitOpen}
+	}
+
+	err := client.retry(ctx, path, params, out)
+	if errors.Is(err, ErrQuotaExhausted) || errors.Is(ctx.Err(), context.Canceled) {
+		breaker.Release()
+	} else {
+		breaker.Record(!isFailure(err))
+	}
+
+	return err
+}
+
+func (client *Client) retry(ctx context.Context, path string, params url.Values, out any) error {
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = client.fetchOnce(ctx, path, params, out)
+		if err == nil || !isRetryable(err) || attempt >= client.MaxRetries {
+			return err
+		}
+
+		// Wait before the next attempt unless the request has been canceled
+		timer := time.NewTimer(client.backoff(attempt, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &UpstreamError{Path: path, Err: ErrTimeout}
+		case <-timer.C:
+		}
+	}
+}
+
+// outcome classifies the result of an upstream call
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrQuotaExhausted):
+		return "quota_exhausted"
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrBadRequest):
+		return "bad_request"
+	case errors.Is(err, ErrInvalidResponse):
+		return "invalid_response"
+	case errors.Is(err, ErrUpstreamDown):
+		return "unavailable"
+	default:
+		return "error"
+	}
+}
+
+func (client *Client) fetchOnce(ctx context.Context, path string, params url.Values, out any) (time.Duration, error) {
+	startTime := time.Now()
+	retryAfter, err := client.call(ctx, path, params, out)
+
+	// Calls refused by the quota never reach the upstream provider
+	endpoint, result, elapsed := endpointType(path), outcome(err), time.Since(startTime)
+	if !errors.Is(err, ErrQuotaExhausted) {
+		metrics.UpstreamDuration.Observe(elapsed.Seconds(), endpoint)
+		types.GetRequestLog(ctx).AddUpstreamCall(elapsed, result)
+	}
+	metrics.UpstreamRequests.Inc(endpoint, result)
+
+	slog.DebugContext(ctx, "upstream call",
+		slog.String("request_id", types.RequestID(ctx)),
+		slog.String("endpoint", endpoint),
+		slog.String("outcome", result),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	)
+
+	return retryAfter, err
+}
+
+func (client *Client) call(ctx context.Context, path string, params url.Values, out any) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	url, err := url.Parse(client.BaseURL + path)
+	if err != nil {
+		return 0, err
+	}
+	url.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// Propagate the request identifier to the upstream provider
+	if requestID := types.RequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	// Refuse upstream calls once the daily quota has been exhausted
+	if client.Budget != nil && !client.Budget.Acquire() {
+		return 0, &UpstreamError{Path: path, Err: ErrQuotaExhausted}
+	}
+
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, &UpstreamError{Path: path, Err: ErrTimeout}
+		}
+
+		return 0, &UpstreamError{Path: path, Err: ErrUpstreamDown}
+	}
+	defer res.Body.Close()
+
+	// Map unsuccessful status codes to upstream errors
+	if res.StatusCode != http.StatusOK {
+		// Drain the body to reuse the underlying connection
+		io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+
+		upstreamErr := &UpstreamError{Path: path, StatusCode: res.StatusCode}
+		switch {
+		case res.StatusCode == http.StatusUnauthorized, res.StatusCode == http.StatusForbidden:
+			upstreamErr.Err = ErrUnauthorized
+		case res.StatusCode == http.StatusTooManyRequests:
+			upstreamErr.Err = ErrRateLimited
+		case res.StatusCode == http.StatusNotFound:
+			upstreamErr.Err = ErrNotFound
+		case res.StatusCode >= 500:
+			upstreamErr.Err = ErrUpstreamDown
+		default:
+			upstreamErr.Err = ErrBadRequest
+		}
+
+		return retryAfter, upstreamErr
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, &UpstreamError{Path: path, Err: ErrTimeout}
+		}
+
+		return 0, &UpstreamError{Path: path, Err: ErrInvalidResponse}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+const weatherRes = `{
+	"current": {
+		"dt": 1750320000,
+		"temp": 33.2,
+		"feels_like": 36.1,
+		"wind_speed": 3.6,
+		"wind_deg": 180,
+		"weather": [{"main": "Clear", "description": "clear sky", "icon": "01d"}]
+	}
+}`
+
+// fakeProvider starts a fake upstream provider replying with the given
+// status codes(in order) and the given body on success
+func fakeProvider(t *testing.T, body string, statusCodes ...int) (*Client, *atomic.Int32) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		idx := int(calls.Add(1)) - 1
+		if idx < len(statusCodes) && statusCodes[idx] != http.StatusOK {
+			res.WriteHeader(statusCodes[idx])
+			return
+		}
+
+		res.Header().Set("Content-Type", "application/json")
+		res.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.BaseURL = server.URL
+	client.Backoff = time.Millisecond
+	client.MaxBackoff = 5 * time.Millisecond
+
+	return client, &calls
+}
+
+func TestClientStatusCodes(t *testing.T) {
+	tests := []struct {
+		Name        string
+		StatusCodes []int
+		Expected    error
+		Calls       int32
+	}{
+		{"Success", []int{http.StatusOK}, nil, 1},
+		{"Unauthorized", []int{http.StatusUnauthorized}, ErrUnauthorized, 1},
+		{"Not found", []int{http.StatusNotFound}, ErrNotFound, 1},
+		{"Bad request", []int{http.StatusBadRequest}, ErrBadRequest, 1},
+		{"Transient failure", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, nil, 3},
+		{"Rate limited", []int{429, 429, 429}, ErrRateLimited, 3},
+		{"Upstream down", []int{500, 500, 500}, ErrUpstreamDown, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client, calls := fakeProvider(t, weatherRes, test.StatusCodes...)
+
+			var out map[string]any
+			err := client.fetch(context.Background(), WTR_PATH, nil, &out)
+
+			if !errors.Is(err, test.Expected) {
+				t.Errorf("Got %v, wanted %v", err, test.Expected)
+			}
+
+			if calls.Load() != test.Calls {
+				t.Errorf("Got %d calls, wanted %d", calls.Load(), test.Calls)
+			}
+		})
+	}
+}
+
+func TestClientInvalidResponse(t *testing.T) {
+	client, _ := fakeProvider(t, "<html>", http.StatusOK)
+
+	var out map[string]any
+	err := client.fetch(context.Background(), WTR_PATH, nil, &out)
+
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("Got %v, wanted %v", err, ErrInvalidResponse)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.BaseURL = server.URL
+	client.Timeout = 10 * time.Millisecond
+	client.MaxRetries = 0
+
+	var out map[string]any
+	err := client.fetch(context.Background(), WTR_PATH, nil, &out)
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Got %v, wanted %v", err, ErrTimeout)
+	}
+}
+
+func TestGetWeather(t *testing.T) {
+	client, _ := fakeProvider(t, weatherRes, http.StatusOK)
+
+	// Point the model layer to the fake provider
+	defaultClient := Upstream
+	Upstream = client
+	t.Cleanup(func() { Upstream = defaultClient })
+
+	got, err := GetWeather(context.Background(), &types.City{Lat: 45.46, Lon: 9.19}, "token")
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if got.Temperature != "33.2" || got.Condition != "Clear" || got.Emoji != "☀️" {
+		t.Errorf("Got %+v, wanted a clear day at 33.2°C", got)
+	}
+}
@@ -1,8 +1,7 @@
 package model
 
 import (
-	"encoding/json"
-	"net/http"
+	"context"
 	"net/url"
 	"strconv"
 	"strings"
@@ -72,30 +71,28 @@ func getForecastEntity(dailyForecast dailyRes) types.ForecastEntity {
 	}
 }
 
-func GetForecast(city *types.City, apiKey string) (types.Forecast, error) {
-	url, err := url.Parse(WTR_URL)
-	if err != nil {
-		return types.Forecast{}, err
-	}
-
-	params := url.Query()
+func GetForecast(ctx context.Context, city *types.City, apiKey string) (types.Forecast, error) {
+	params := url.Values{}
 	params.Set("lat", strconv.FormatFloat(city.Lat, 'f', -1, 64))
 	params.Set("lon", strconv.FormatFloat(city.Lon, 'f', -1, 64))
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "current,minutely,hourly,alerts")
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
+	var forecastRes forecastRes
+	if err := Upstream.fetch(ctx, WTR_PATH, params, &forecastRes); err != nil {
 		return types.Forecast{}, err
 	}
-	defer res.Body.Close()
 
-	var forecastRes forecastRes
-	if err := json.NewDecoder(res.Body).Decode(&forecastRes); err != nil {
-		return types.Forecast{}, err
+	// Reject responses without enough daily forecasts
+	if len(forecastRes.Daily) < 5 {
+		return types.Forecast{}, &UpstreamError{Path: WTR_PATH, Err: ErrInvalidResponse}
+	}
+
+	for _, val := range forecastRes.Daily {
+		if len(val.Weather) == 0 {
+			return types.Forecast{}, &UpstreamError{Path: WTR_PATH, Err: ErrInvalidResponse}
+		}
 	}
 
 	// We skip the first element since it represents the current day
@@ -1,45 +1,78 @@
 package model
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func GetCoordinates(cityName string, apiKey string) (types.City, error) {
-	url, err := url.Parse(GEO_URL)
-	if err != nil {
-		return types.City{}, err
+// Maximum number of locations returned by the geocoding service
+const maxCandidates = "5"
+
+// Errors returned by the geocoding service
+var (
+	ErrCityNotFound = errors.New("Cannot find this city")
+	ErrZipNotFound  = errors.New("Cannot find this postal code")
+)
+
+func GetCandidates(ctx context.Context, query string, apiKey string) (types.Candidates, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", maxCandidates)
+	params.Set("appid", apiKey)
+
+	var geoArr types.Candidates
+	if err := Upstream.fetch(ctx, GEO_PATH, params, &geoArr); err != nil {
+		return nil, err
 	}
 
-	params := url.Query()
-	params.Set("q", cityName)
+	if len(geoArr) == 0 {
+		return nil, ErrCityNotFound
+	}
+
+	return geoArr, nil
+}
+
+func GetLocationByCoords(ctx context.Context, lat float64, lon float64, apiKey string) (types.City, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
 	params.Set("limit", "1")
 	params.Set("appid", apiKey)
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
+	var geoArr types.Candidates
+	if err := Upstream.fetch(ctx, REVERSE_PATH, params, &geoArr); err != nil {
 		return types.City{}, err
 	}
-	defer res.Body.Close()
 
-	var geoArr []types.City
-	if err := json.NewDecoder(res.Body).Decode(&geoArr); err != nil {
-		return types.City{}, err
+	// Locations far from any inhabited place(e.g. oceans) have no name,
+	// but their weather data is still available
+	location := types.City{Lat: lat, Lon: lon}
+	if len(geoArr) > 0 {
+		location.Name = geoArr[0].Name
+		location.Country = geoArr[0].Country
+		location.State = geoArr[0].State
 	}
 
-	if len(geoArr) == 0 {
-		return types.City{}, errors.New("Cannot find this city")
+	return location, nil
+}
+
+func GetLocationByZip(ctx context.Context, zipCode string, apiKey string) (types.City, error) {
+	params := url.Values{}
+	params.Set("zip", zipCode)
+	params.Set("appid", apiKey)
+
+	var location types.City
+	if err := Upstream.fetch(ctx, ZIP_PATH, params, &location); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return types.City{}, ErrZipNotFound
+		}
+
+		return types.City{}, err
 	}
 
-	return types.City{
-		Name: geoArr[0].Name,
-		Lat:  geoArr[0].Lat,
-		Lon:  geoArr[0].Lon,
-	}, nil
+	return location, nil
 }
@@ -1,36 +1,22 @@
 package model
 
 import (
-	"encoding/json"
+	"context"
 	"math"
-	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func GetMetrics(city *types.City, apiKey string) (types.Metrics, error) {
-	url, err := url.Parse(WTR_URL)
-	if err != nil {
-		return types.Metrics{}, err
-	}
-
-	params := url.Query()
+func GetMetrics(ctx context.Context, city *types.City, apiKey string) (types.Metrics, error) {
+	params := url.Values{}
 	params.Set("lat", strconv.FormatFloat(city.Lat, 'f', -1, 64))
 	params.Set("lon", strconv.FormatFloat(city.Lon, 'f', -1, 64))
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "minutely,hourly,daily,alerts")
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
-		return types.Metrics{}, err
-	}
-	defer res.Body.Close()
-
 	// Structure representing the JSON response
 	type MetricsRes struct {
 		Current struct {
@@ -43,7 +29,7 @@ func GetMetrics(city *types.City, apiKey string) (types.Metrics, error) {
 	}
 
 	var metricRes MetricsRes
-	if err := json.NewDecoder(res.Body).Decode(&metricRes); err != nil {
+	if err := Upstream.fetch(ctx, WTR_PATH, params, &metricRes); err != nil {
 		return types.Metrics{}, err
 	}
 
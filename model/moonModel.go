@@ -1,9 +1,8 @@
 package model
 
 import (
-	"encoding/json"
+	"context"
 	"math"
-	"net/http"
 	"net/url"
 	"strconv"
 
@@ -38,27 +37,14 @@ func getMoonPhase(moonValue float64) (string, string) {
 	return "❓", "Unknown moon phase"
 }
 
-func GetMoon(apiKey string) (types.Moon, error) {
-	url, err := url.Parse(WTR_URL)
-	if err != nil {
-		return types.Moon{}, err
-	}
-
-	params := url.Query()
+func GetMoon(ctx context.Context, apiKey string) (types.Moon, error) {
+	params := url.Values{}
 	params.Set("lat", "41.8933203") // Rome latitude
 	params.Set("lon", "12.4829321") // Rome longitude
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "current,hourly,alerts")
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
-		return types.Moon{}, err
-	}
-	defer res.Body.Close()
-
 	// Structure representing the JSON response
 	type MoonRes struct {
 		Daily []struct {
@@ -67,10 +53,14 @@ func GetMoon(apiKey string) (types.Moon, error) {
 	}
 
 	var moonRes MoonRes
-	if err := json.NewDecoder(res.Body).Decode(&moonRes); err != nil {
+	if err := Upstream.fetch(ctx, WTR_PATH, params, &moonRes); err != nil {
 		return types.Moon{}, err
 	}
 
+	if len(moonRes.Daily) == 0 {
+		return types.Moon{}, &UpstreamError{Path: WTR_PATH, Err: ErrInvalidResponse}
+	}
+
 	// Retrieve moon icon and moon phase(description) from moon phase(value)
 	icon, phase := getMoonPhase(moonRes.Daily[0].Value)
 
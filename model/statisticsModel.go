@@ -9,10 +9,12 @@ import (
 	"github.com/ceticamarco/zephyr/types"
 )
 
+var ErrInsufficientData = errors.New("Insufficient or outdated data to perform statistical analysis")
+
 func GetStatistics(cityName string, statDB *types.StatDB) (types.StatResult, error) {
 	// Check whether there are sufficient and updated records for the given location
 	if statDB.IsKeyInvalid(cityName) {
-		return types.StatResult{}, errors.New("Insufficient or outdated data to perform statistical analysis")
+		return types.StatResult{}, ErrInsufficientData
 	}
 
 	extractTemps := func(weatherArr []types.Weather) ([]float64, error) {
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+const (
+	unixEpochJD = 2440587.5 // Julian date of the UNIX epoch
+	j2000JD     = 2451545.0 // Julian date of the J2000 epoch
+	secondsDay  = 24 * 60 * 60
+)
+
+func sinDeg(deg float64) float64 {
+	return math.Sin(deg * math.Pi / 180)
+}
+
+func cosDeg(deg float64) float64 {
+	return math.Cos(deg * math.Pi / 180)
+}
+
+func toJulian(date time.Time) float64 {
+	return float64(date.Unix())/secondsDay + unixEpochJD
+}
+
+func fromJulian(julian float64) time.Time {
+	return time.Unix(int64(math.Round((julian-unixEpochJD)*secondsDay)), 0).UTC()
+}
+
+// GetSun computes the sunrise and the sunset of a location on the day
+// of the given date(in UTC) through the sunrise equation, with an accuracy
+// of about a minute. Unlike the other data, it does not require upstream calls
+func GetSun(city *types.City, date time.Time) types.Sun {
+	day := date.UTC().Truncate(24 * time.Hour)
+
+	// Days since J2000 and mean solar time at the location
+	days := math.Ceil(toJulian(day) - j2000JD + 0.0008)
+	meanSolarTime := days - city.Lon/360
+
+	// Solar mean anomaly, equation of the center and ecliptic longitude
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	center := 1.9148*sinDeg(anomaly) + 0.02*sinDeg(2*anomaly) + 0.0003*sinDeg(3*anomaly)
+	longitude := math.Mod(anomaly+center+180+102.9372, 360)
+
+	// Solar transit(i.e. local noon) and declination of the sun
+	transit := j2000JD + meanSolarTime + 0.0053*sinDeg(anomaly) - 0.0069*sinDeg(2*longitude)
+	sinDeclination := sinDeg(longitude) * sinDeg(23.4397)
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+
+	// Hour angle of the sun when its upper edge touches the horizon,
+	// accounting for the atmospheric refraction(-0.833°)
+	cosHourAngle := (sinDeg(-0.833) - sinDeg(city.Lat)*sinDeclination) / (cosDeg(city.Lat) * cosDeclination)
+	switch {
+	case cosHourAngle > 1:
+		// Polar night, the sun never rises
+		return types.Sun{}
+	case cosHourAngle < -1:
+		// Polar day, the sun never sets
+		return types.Sun{DayLength: secondsDay}
+	}
+
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+	sunrise := fromJulian(transit - hourAngle/360)
+	sunset := fromJulian(transit + hourAngle/360)
+
+	return types.Sun{
+		Sunrise:   &sunrise,
+		Sunset:    &sunset,
+		DayLength: int(sunset.Sub(sunrise).Seconds()),
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetSun(t *testing.T) {
+	milan := &types.City{Lat: 45.46, Lon: 9.19}
+	tromso := &types.City{Lat: 69.65, Lon: 18.96}
+
+	tests := []struct {
+		Name      string
+		City      *types.City
+		Date      time.Time
+		Sunrise   string
+		Sunset    string
+		DayLength int
+	}{
+		{"Summer solstice", milan, time.Date(2025, 6, 21, 15, 0, 0, 0, time.UTC), "03:35", "19:16", -1},
+		{"Winter solstice", milan, time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC), "07:02", "15:42", -1},
+		{"Polar day", tromso, time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC), "", "", 24 * 60 * 60},
+		{"Polar night", tromso, time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC), "", "", 0},
+	}
+
+	// Compare times up to a couple of minutes
+	isClose := func(got *time.Time, date time.Time, expected string) bool {
+		if got == nil || expected == "" {
+			return got == nil && expected == ""
+		}
+
+		parsed, _ := time.Parse("15:04", expected)
+		wanted := time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+
+		return got.Sub(wanted).Abs() <= 2*time.Minute
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := GetSun(test.City, test.Date)
+
+			if !isClose(got.Sunrise, test.Date, test.Sunrise) || !isClose(got.Sunset, test.Date, test.Sunset) {
+				t.Errorf("Got (%v, %v), wanted (%s, %s)", got.Sunrise, got.Sunset, test.Sunrise, test.Sunset)
+			}
+
+			if test.DayLength != -1 && got.DayLength != test.DayLength {
+				t.Errorf("Got %d, wanted %d", got.DayLength, test.DayLength)
+			}
+		})
+	}
+}
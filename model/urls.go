@@ -1,6 +1,24 @@
 package model
 
 const (
-	GEO_URL = "https://api.openweathermap.org/geo/1.0/direct"
-	WTR_URL = "https://api.openweathermap.org/data/3.0/onecall"
+	BASE_URL     = "https://api.openweathermap.org"
+	GEO_PATH     = "/geo/1.0/direct"
+	REVERSE_PATH = "/geo/1.0/reverse"
+	ZIP_PATH     = "/geo/1.0/zip"
+	WTR_PATH     = "/data/3.0/onecall"
 )
+
+// Upstream endpoint types, each one guarded by its own circuit breaker
+const (
+	ENDPOINT_GEOCODING = "geocoding"
+	ENDPOINT_ONECALL   = "onecall"
+)
+
+func endpointType(path string) string {
+	switch path {
+	case GEO_PATH, REVERSE_PATH, ZIP_PATH:
+		return ENDPOINT_GEOCODING
+	default:
+		return ENDPOINT_ONECALL
+	}
+}
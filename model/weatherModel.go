@@ -1,8 +1,7 @@
 package model
 
 import (
-	"encoding/json"
-	"net/http"
+	"context"
 	"net/url"
 	"strconv"
 	"strings"
@@ -42,27 +41,14 @@ func GetEmoji(condition string, isNight bool) string {
 	return "❓"
 }
 
-func GetWeather(city *types.City, apiKey string) (types.Weather, error) {
-	url, err := url.Parse(WTR_URL)
-	if err != nil {
-		return types.Weather{}, err
-	}
-
-	params := url.Query()
+func GetWeather(ctx context.Context, city *types.City, apiKey string) (types.Weather, error) {
+	params := url.Values{}
 	params.Set("lat", strconv.FormatFloat(city.Lat, 'f', -1, 64))
 	params.Set("lon", strconv.FormatFloat(city.Lon, 'f', -1, 64))
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "minutely,hourly,daily,alerts")
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
-		return types.Weather{}, err
-	}
-	defer res.Body.Close()
-
 	// Structure representing the JSON response
 	type WeatherRes struct {
 		Current struct {
@@ -78,10 +64,14 @@ func GetWeather(city *types.City, apiKey string) (types.Weather, error) {
 	}
 
 	var weather WeatherRes
-	if err := json.NewDecoder(res.Body).Decode(&weather); err != nil {
+	if err := Upstream.fetch(ctx, WTR_PATH, params, &weather); err != nil {
 		return types.Weather{}, err
 	}
 
+	if len(weather.Current.Weather) == 0 {
+		return types.Weather{}, &UpstreamError{Path: WTR_PATH, Err: ErrInvalidResponse}
+	}
+
 	// Format UNIX timestamp as 'YYYY-MM-DD'
 	utcTime := time.Unix(int64(weather.Current.Timestamp), 0)
 	weatherDate := types.ZephyrDate{Date: utcTime.UTC()}
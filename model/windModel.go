@@ -1,9 +1,8 @@
 package model
 
 import (
-	"encoding/json"
+	"context"
 	"math"
-	"net/http"
 	"net/url"
 	"strconv"
 
@@ -40,28 +39,14 @@ func GetCardinalDir(windDeg float64) (string, string) {
 
 }
 
-func GetWind(city *types.City, apiKey string) (types.Wind, error) {
-	url, err := url.Parse(WTR_URL)
-	if err != nil {
-		return types.Wind{}, err
-	}
-
-	params := url.Query()
+func GetWind(ctx context.Context, city *types.City, apiKey string) (types.Wind, error) {
+	params := url.Values{}
 	params.Set("lat", strconv.FormatFloat(city.Lat, 'f', -1, 64))
 	params.Set("lon", strconv.FormatFloat(city.Lon, 'f', -1, 64))
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "minutely,hourly,daily,alerts")
 
-	url.RawQuery = params.Encode()
-
-	res, err := http.Get(url.String())
-	if err != nil {
-		return types.Wind{}, err
-	}
-
-	defer res.Body.Close()
-
 	// Structure representing the JSON response
 	type WindRes struct {
 		Current struct {
@@ -71,7 +56,7 @@ func GetWind(city *types.City, apiKey string) (types.Wind, error) {
 	}
 
 	var windRes WindRes
-	if err := json.NewDecoder(res.Body).Decode(&windRes); err != nil {
+	if err := Upstream.fetch(ctx, WTR_PATH, params, &windRes); err != nil {
 		return types.Wind{}, err
 	}
 
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+var update = flag.Bool("update", false, "Regenerate the OpenAPI document")
+
+const documentPath = "../openapi.json"
+
+// sample builds a value of the given type whose fields are all set,
+// so that every property appears in its JSON encoding
+func sample(typ reflect.Type) reflect.Value {
+	val := reflect.New(typ).Elem()
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		val.Set(reflect.New(typ.Elem()))
+		val.Elem().Set(sample(typ.Elem()))
+	case reflect.String:
+		val.SetString("value")
+	case reflect.Bool:
+		val.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		val.SetFloat(1.5)
+	case reflect.Slice:
+		val.Set(reflect.Append(reflect.MakeSlice(typ, 0, 1), sample(typ.Elem())))
+	case reflect.Struct:
+		if typ == reflect.TypeFor[time.Time]() {
+			val.Set(reflect.ValueOf(time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC)))
+			break
+		}
+
+		for idx := range typ.NumField() {
+			if typ.Field(idx).IsExported() {
+				val.Field(idx).Set(sample(typ.Field(idx).Type))
+			}
+		}
+	}
+
+	return val
+}
+
+// validate checks a decoded JSON value against a schema, resolving references
+func validate(doc *Document, schema *Schema, val any, path string) error {
+	if ref, found := strings.CutPrefix(schema.Ref, "#/components/schemas/"); found {
+		resolved, isPresent := doc.Components.Schemas[ref]
+		if !isPresent {
+			return fmt.Errorf("%s: unknown schema '%s'", path, ref)
+		}
+
+		return validate(doc, resolved, val, path)
+	}
+
+	if val == nil {
+		if !schema.Nullable {
+			return fmt.Errorf("%s: null value of a non-nullable property", path)
+		}
+
+		return nil
+	}
+
+	switch val := val.(type) {
+	case string:
+		if schema.Type != "string" {
+			return fmt.Errorf("%s: got a string, wanted %s", path, schema.Type)
+		}
+	case bool:
+		if schema.Type != "boolean" {
+			return fmt.Errorf("%s: got a boolean, wanted %s", path, schema.Type)
+		}
+	case float64:
+		if schema.Type != "number" && schema.Type != "integer" {
+			return fmt.Errorf("%s: got a number, wanted %s", path, schema.Type)
+		}
+	case []any:
+		if schema.Type != "array" {
+			return fmt.Errorf("%s: got an array, wanted %s", path, schema.Type)
+		}
+
+		for idx, item := range val {
+			if err := validate(doc, schema.Items, item, fmt.Sprintf("%s[%d]", path, idx)); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		if schema.Type != "object" {
+			return fmt.Errorf("%s: got an object, wanted %s", path, schema.Type)
+		}
+
+		for _, name := range schema.Required {
+			if _, isPresent := val[name]; !isPresent {
+				return fmt.Errorf("%s: missing required property '%s'", path, name)
+			}
+		}
+
+		for name, item := range val {
+			property, isPresent := schema.Properties[name]
+			if !isPresent {
+				return fmt.Errorf("%s: undocumented property '%s'", path, name)
+			}
+
+			if err := validate(doc, property, item, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestResponseTypes(t *testing.T) {
+	doc := Build()
+
+	tests := []struct {
+		Path  string
+		Value any
+	}{
+		{"/weather/{city}", types.Weather{}},
+		{"/metrics/{city}", types.Metrics{}},
+		{"/wind/{city}", types.Wind{}},
+		{"/forecast/{city}", types.Forecast{}},
+		{"/moon", types.Moon{}},
+		{"/stats/{city}", types.StatResult{}},
+		{"/v2/weather/{city}", types.WeatherV2{}},
+		{"/v2/metrics/{city}", types.MetricsV2{}},
+		{"/v2/wind/{city}", types.CurrentWindV2{}},
+		{"/v2/forecast/{city}", types.ForecastV2{}},
+		{"/v2/moon", types.MoonV2{}},
+		{"/v2/stats/{city}", types.StatResultV2{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			operation, isPresent := doc.Paths[test.Path]["get"]
+			if !isPresent {
+				t.Fatalf("Got no operation, wanted GET %s", test.Path)
+			}
+
+			content, err := json.Marshal(sample(reflect.TypeOf(test.Value)).Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var decoded any
+			json.Unmarshal(content, &decoded)
+
+			schema := operation.Responses["200"].Content["application/json"].Schema
+			if err := validate(&doc, schema, decoded, "response"); err != nil {
+				t.Errorf("Got %v, wanted %s to match the specification", err, content)
+			}
+		})
+	}
+}
+
+// TestDocument compares the generated document with the published one,
+// run 'go test ./openapi -update' after changing the response types
+func TestDocument(t *testing.T) {
+	generated, err := json.MarshalIndent(Build(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated = append(generated, '\n')
+
+	if *update {
+		if err := os.WriteFile(documentPath, generated, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	published, err := os.ReadFile(documentPath)
+	if err != nil {
+		t.Fatalf("Got %v, wanted the published document", err)
+	}
+
+	if !bytes.Equal(generated, published) {
+		t.Errorf("The published document is outdated, run 'go test ./openapi -update'")
+	}
+}
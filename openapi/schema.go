@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema, representing an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Example              any                `json:"example,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Generator, representing a set of schemas generated from Go types.
+// Named structures are registered as components and referenced
+// through '$ref', so that each of them is described only once
+type Generator struct {
+	Schemas   map[string]*Schema
+	overrides map[reflect.Type]*Schema
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		Schemas:   make(map[string]*Schema),
+		overrides: make(map[reflect.Type]*Schema),
+	}
+}
+
+// Override describes a type through a fixed schema, which is required
+// by types implementing their own JSON encoding(e.g. dates)
+func (gen *Generator) Override(val any, schema *Schema) {
+	gen.overrides[reflect.TypeOf(val)] = schema
+}
+
+// Ref returns a schema referencing the component generated from a value
+func (gen *Generator) Ref(val any) *Schema {
+	return gen.schemaOf(reflect.TypeOf(val))
+}
+
+func (gen *Generator) schemaOf(typ reflect.Type) *Schema {
+	if schema, isPresent := gen.overrides[typ]; isPresent {
+		copied := *schema
+		return &copied
+	}
+
+	// Custom encodings cannot be described through reflection
+	if typ.Implements(reflect.TypeFor[json.Marshaler]()) || reflect.PointerTo(typ).Implements(reflect.TypeFor[json.Marshaler]()) {
+		if typ != reflect.TypeFor[time.Time]() {
+			panic(fmt.Sprintf("openapi: type %s has a custom JSON encoding, register an override", typ))
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		schema := gen.schemaOf(typ.Elem())
+		schema.Nullable = true
+
+		// References cannot be combined with other keywords
+		if schema.Ref != "" {
+			return &Schema{Ref: schema.Ref}
+		}
+
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: gen.schemaOf(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: gen.schemaOf(typ.Elem())}
+	case reflect.Struct:
+		if typ == reflect.TypeFor[time.Time]() {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+
+		if typ.Name() == "" {
+			return gen.objectOf(typ)
+		}
+
+		if _, isPresent := gen.Schemas[typ.Name()]; !isPresent {
+			gen.Schemas[typ.Name()] = nil // Guard against recursive types
+			gen.Schemas[typ.Name()] = gen.objectOf(typ)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + typ.Name()}
+	case reflect.Interface:
+		return &Schema{}
+	default:
+		panic(fmt.Sprintf("openapi: unsupported type %s", typ))
+	}
+}
+
+// objectOf describes the fields of a structure according to their JSON tags.
+// Fields without 'omitempty' are always encoded, hence they are required
+func (gen *Generator) objectOf(typ reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for idx := range typ.NumField() {
+		field := typ.Field(idx)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		// Embedded structures are flattened into their parent
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := gen.objectOf(field.Type)
+			for key, val := range embedded.Properties {
+				schema.Properties[key] = val
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = gen.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
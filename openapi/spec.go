@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Version of the API described by the document
+const APIVersion = "2.0.0"
+
+// Document, representing an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type Operation struct {
+	Summary    string                `json:"summary"`
+	Tags       []string              `json:"tags"`
+	Parameters []Parameter           `json:"parameters,omitempty"`
+	Responses  map[string]Response   `json:"responses"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Problem, representing the RFC 7807 problem details returned on errors
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// ErrorV2, representing the envelope of the errors returned by the '/v2/' API
+type ErrorV2 struct {
+	Error struct {
+		Code      string `json:"code"`
+		Status    int    `json:"status"`
+		Title     string `json:"title"`
+		Detail    string `json:"detail"`
+		RequestID string `json:"requestId,omitempty"`
+	} `json:"error"`
+}
+
+// Query parameters shared by the endpoints
+var (
+	imperialParam = Parameter{
+		Name:        "i",
+		In:          "query",
+		Description: "Use imperial units(any value, e.g. '?i')",
+		Schema:      &Schema{Type: "string"},
+	}
+	unitsParam = Parameter{
+		Name:        "units",
+		In:          "query",
+		Description: "System of units, either 'metric'(default) or 'imperial'",
+		Schema:      &Schema{Type: "string"},
+	}
+	countryParam = Parameter{
+		Name:        "country",
+		In:          "query",
+		Description: "ISO 3166 country code of the city(e.g. 'IT')",
+		Schema:      &Schema{Type: "string"},
+	}
+	cityParam = Parameter{
+		Name:        "city",
+		In:          "path",
+		Description: "City name, optionally qualified as 'city,state,country'(e.g. 'Milan,IT')",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}
+	// Coordinates and postal codes take precedence over the city name, if any
+	locationParams = []Parameter{
+		{Name: "lat", In: "query", Description: "Latitude, along with 'lon'", Schema: &Schema{Type: "number"}},
+		{Name: "lon", In: "query", Description: "Longitude, along with 'lat'", Schema: &Schema{Type: "number"}},
+		{Name: "zip", In: "query", Description: "Postal code and country code(e.g. '10001,US')", Schema: &Schema{Type: "string"}},
+	}
+)
+
+// newZephyrGenerator returns a generator describing the custom types of Zephyr
+func newZephyrGenerator() *Generator {
+	gen := NewGenerator()
+	gen.Override(types.ZephyrDate{}, &Schema{
+		Type:        "string",
+		Description: "Date formatted as 'Weekday, YYYY/MM/DD'",
+		Example:     "Thursday, 2025/06/19",
+	})
+
+	return gen
+}
+
+// apiVersion describes how the endpoints of a version of the API
+// report errors and select the system of units
+type apiVersion struct {
+	prefix string
+	errors func(gen *Generator) map[string]MediaType
+	units  Parameter
+}
+
+var (
+	v1 = apiVersion{
+		prefix: "/",
+		errors: func(gen *Generator) map[string]MediaType {
+			return map[string]MediaType{"application/problem+json": {Schema: gen.Ref(Problem{})}}
+		},
+		units: imperialParam,
+	}
+	v2 = apiVersion{
+		prefix: "/v2/",
+		errors: func(gen *Generator) map[string]MediaType {
+			return map[string]MediaType{"application/json": {Schema: gen.Ref(ErrorV2{})}}
+		},
+		units: unitsParam,
+	}
+)
+
+// responses describes the outcomes of a data endpoint, including the errors
+// shared by every endpoint and, for located queries, the geocoding ones
+func responses(gen *Generator, version apiVersion, summary string, val any, isLocated bool) map[string]Response {
+	problem := version.errors(gen)
+
+	outcomes := map[string]Response{
+		"200": {Description: summary, Content: map[string]MediaType{"application/json": {Schema: gen.Ref(val)}}},
+		"304": {Description: "Not modified since the last request"},
+		"400": {Description: "Invalid request", Content: problem},
+		"401": {Description: "Invalid or missing API key", Content: problem},
+		"429": {Description: "Rate limit exceeded", Content: problem},
+		"502": {Description: "Invalid response from the upstream provider", Content: problem},
+		"503": {Description: "Upstream provider unavailable", Content: problem},
+		"504": {Description: "Upstream provider timeout", Content: problem},
+	}
+
+	if isLocated {
+		outcomes["300"] = Response{Description: "Ambiguous city name(strict geocoding only)", Content: problem}
+		outcomes["404"] = Response{Description: "Location not found", Content: problem}
+	}
+
+	return outcomes
+}
+
+// get describes a GET endpoint, which can be called either
+// anonymously or through an API key
+func (doc *Document) get(path string, summary string, tag string, outcomes map[string]Response, params ...Parameter) {
+	doc.Paths[path] = map[string]Operation{
+		strings.ToLower(http.MethodGet): {
+			Summary:    summary,
+			Tags:       []string{tag},
+			Parameters: params,
+			Responses:  outcomes,
+			Security:   []map[string][]string{{}, {"apiKey": {}}},
+		},
+	}
+}
+
+// getLocated describes an endpoint both by city name('/weather/{city}')
+// and by coordinates or postal code('/weather/?lat=...&lon=...')
+func (doc *Document) getLocated(gen *Generator, version apiVersion, name string, summary string, val any) {
+	prefix := version.prefix + name + "/"
+	outcomes := responses(gen, version, summary, val, true)
+	if name == "stats" {
+		outcomes["422"] = Response{Description: "Insufficient or outdated data to perform statistical analysis", Content: version.errors(gen)}
+	}
+
+	cityParams := append([]Parameter{cityParam, countryParam}, locationParams...)
+	doc.get(prefix+"{city}", summary, "weather", outcomes, append(cityParams, version.units)...)
+	doc.get(prefix, summary+" by coordinates or postal code", "weather", outcomes, append(slices.Clone(locationParams), version.units)...)
+}
+
+// getGeocode describes the geocoding endpoint
+func (doc *Document) getGeocode(gen *Generator, version apiVersion) {
+	geocode := struct {
+		Candidates types.Candidates `json:"candidates"`
+	}{}
+	doc.get(version.prefix+"geocode", "Locations matching a city name", "geocoding", responses(gen, version, "Locations matching a city name", geocode, true), Parameter{
+		Name:        "q",
+		In:          "query",
+		Description: "City name, optionally qualified as 'city,state,country'",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}, countryParam)
+}
+
+// Build returns the OpenAPI document of the Zephyr API
+func Build() Document {
+	gen := newZephyrGenerator()
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Zephyr",
+			Description: "Weather forecast service built on top of OpenWeatherMap",
+			Version:     APIVersion,
+		},
+		Paths: make(map[string]map[string]Operation),
+	}
+
+	doc.getLocated(gen, v1, "weather", "Current weather", types.Weather{})
+	doc.getLocated(gen, v1, "metrics", "Current humidity, pressure, dew point, UV index and visibility", types.Metrics{})
+	doc.getLocated(gen, v1, "wind", "Current wind", types.Wind{})
+	doc.getLocated(gen, v1, "forecast", "Daily forecast of the next days", types.Forecast{})
+	doc.getLocated(gen, v1, "stats", "Statistics of the recorded temperatures", types.StatResult{})
+	doc.get("/moon", "Current moon phase", "weather", responses(gen, v1, "Current moon phase", types.Moon{}, false))
+	doc.getGeocode(gen, v1)
+
+	// Version 2, returning numbers along with their units
+	doc.getLocated(gen, v2, "weather", "Current weather", types.WeatherV2{})
+	doc.getLocated(gen, v2, "metrics", "Current humidity(%), pressure, dew point, UV index and visibility", types.MetricsV2{})
+	doc.getLocated(gen, v2, "wind", "Current wind", types.CurrentWindV2{})
+	doc.getLocated(gen, v2, "forecast", "Daily forecast of the next days", types.ForecastV2{})
+	doc.getLocated(gen, v2, "stats", "Statistics of the recorded temperatures", types.StatResultV2{})
+	doc.get("/v2/moon", "Current moon phase", "weather", responses(gen, v2, "Current moon phase", types.MoonV2{}, false))
+	doc.getGeocode(gen, v2)
+
+	doc.Components = Components{
+		Schemas: gen.Schemas,
+		SecuritySchemes: map[string]SecurityScheme{
+			"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		},
+	}
+
+	return doc
+}
@@ -0,0 +1,5 @@
+// Package rpc holds the protobuf messages and the gRPC service of Zephyr,
+// generated from 'zephyr.proto'. The service is implemented by the controllers
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative zephyr.proto
@@ -0,0 +1,1311 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: zephyr.proto
+
+// Weather data served over gRPC, backed by the same caches and
+// statistical database of the HTTP API. Measures are numbers
+// expressed in the units listed by each message, as in the '/v2/' API
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UnitSystem int32
+
+const (
+	UnitSystem_UNIT_SYSTEM_METRIC   UnitSystem = 0
+	UnitSystem_UNIT_SYSTEM_IMPERIAL UnitSystem = 1
+)
+
+// Enum value maps for UnitSystem.
+var (
+	UnitSystem_name = map[int32]string{
+		0: "UNIT_SYSTEM_METRIC",
+		1: "UNIT_SYSTEM_IMPERIAL",
+	}
+	UnitSystem_value = map[string]int32{
+		"UNIT_SYSTEM_METRIC":   0,
+		"UNIT_SYSTEM_IMPERIAL": 1,
+	}
+)
+
+func (x UnitSystem) Enum() *UnitSystem {
+	p := new(UnitSystem)
+	*p = x
+	return p
+}
+
+func (x UnitSystem) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UnitSystem) Descriptor() protoreflect.EnumDescriptor {
+	return file_zephyr_proto_enumTypes[0].Descriptor()
+}
+
+func (UnitSystem) Type() protoreflect.EnumType {
+	return &file_zephyr_proto_enumTypes[0]
+}
+
+func (x UnitSystem) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UnitSystem.Descriptor instead.
+func (UnitSystem) EnumDescriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{0}
+}
+
+type Coordinates struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Coordinates) Reset() {
+	*x = Coordinates{}
+	mi := &file_zephyr_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinates) ProtoMessage() {}
+
+func (x *Coordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinates.ProtoReflect.Descriptor instead.
+func (*Coordinates) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Coordinates) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Coordinates) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+// Location to query, either by city name, by coordinates or by postal code
+type LocationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Query:
+	//
+	//	*LocationRequest_City
+	//	*LocationRequest_Coordinates
+	//	*LocationRequest_Zip
+	Query         isLocationRequest_Query `protobuf_oneof:"query"`
+	Country       string                  `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"` // ISO 3166 country code of the city
+	Units         UnitSystem              `protobuf:"varint,5,opt,name=units,proto3,enum=zephyr.v1.UnitSystem" json:"units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationRequest) Reset() {
+	*x = LocationRequest{}
+	mi := &file_zephyr_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationRequest) ProtoMessage() {}
+
+func (x *LocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationRequest.ProtoReflect.Descriptor instead.
+func (*LocationRequest) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LocationRequest) GetQuery() isLocationRequest_Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *LocationRequest) GetCity() string {
+	if x != nil {
+		if x, ok := x.Query.(*LocationRequest_City); ok {
+			return x.City
+		}
+	}
+	return ""
+}
+
+func (x *LocationRequest) GetCoordinates() *Coordinates {
+	if x != nil {
+		if x, ok := x.Query.(*LocationRequest_Coordinates); ok {
+			return x.Coordinates
+		}
+	}
+	return nil
+}
+
+func (x *LocationRequest) GetZip() string {
+	if x != nil {
+		if x, ok := x.Query.(*LocationRequest_Zip); ok {
+			return x.Zip
+		}
+	}
+	return ""
+}
+
+func (x *LocationRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *LocationRequest) GetUnits() UnitSystem {
+	if x != nil {
+		return x.Units
+	}
+	return UnitSystem_UNIT_SYSTEM_METRIC
+}
+
+type isLocationRequest_Query interface {
+	isLocationRequest_Query()
+}
+
+type LocationRequest_City struct {
+	City string `protobuf:"bytes,1,opt,name=city,proto3,oneof"` // Optionally qualified as 'city,state,country'(e.g. 'Milan,IT')
+}
+
+type LocationRequest_Coordinates struct {
+	Coordinates *Coordinates `protobuf:"bytes,2,opt,name=coordinates,proto3,oneof"`
+}
+
+type LocationRequest_Zip struct {
+	Zip string `protobuf:"bytes,3,opt,name=zip,proto3,oneof"` // Postal code and country code(e.g. '10001,US')
+}
+
+func (*LocationRequest_City) isLocationRequest_Query() {}
+
+func (*LocationRequest_Coordinates) isLocationRequest_Query() {}
+
+func (*LocationRequest_Zip) isLocationRequest_Query() {}
+
+type MoonRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoonRequest) Reset() {
+	*x = MoonRequest{}
+	mi := &file_zephyr_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoonRequest) ProtoMessage() {}
+
+func (x *MoonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoonRequest.ProtoReflect.Descriptor instead.
+func (*MoonRequest) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{2}
+}
+
+type Units struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Temperature   string                 `protobuf:"bytes,1,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Speed         string                 `protobuf:"bytes,2,opt,name=speed,proto3" json:"speed,omitempty"`
+	Pressure      string                 `protobuf:"bytes,3,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	Distance      string                 `protobuf:"bytes,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Units) Reset() {
+	*x = Units{}
+	mi := &file_zephyr_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Units) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Units) ProtoMessage() {}
+
+func (x *Units) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Units.ProtoReflect.Descriptor instead.
+func (*Units) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Units) GetTemperature() string {
+	if x != nil {
+		return x.Temperature
+	}
+	return ""
+}
+
+func (x *Units) GetSpeed() string {
+	if x != nil {
+		return x.Speed
+	}
+	return ""
+}
+
+func (x *Units) GetPressure() string {
+	if x != nil {
+		return x.Pressure
+	}
+	return ""
+}
+
+func (x *Units) GetDistance() string {
+	if x != nil {
+		return x.Distance
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Lat           float64                `protobuf:"fixed64,4,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,5,opt,name=lon,proto3" json:"lon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_zephyr_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Location) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Location) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+type Weather struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Temperature   float64                `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Condition     string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"`
+	FeelsLike     float64                `protobuf:"fixed64,4,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	Emoji         string                 `protobuf:"bytes,5,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Units         *Units                 `protobuf:"bytes,6,opt,name=units,proto3" json:"units,omitempty"`
+	Location      *Location              `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Weather) Reset() {
+	*x = Weather{}
+	mi := &file_zephyr_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Weather) ProtoMessage() {}
+
+func (x *Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Weather.ProtoReflect.Descriptor instead.
+func (*Weather) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Weather) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Weather) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *Weather) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *Weather) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *Weather) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *Weather) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *Weather) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type Metrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Humidity      int32                  `protobuf:"varint,1,opt,name=humidity,proto3" json:"humidity,omitempty"` // Percentage
+	Pressure      int32                  `protobuf:"varint,2,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	DewPoint      float64                `protobuf:"fixed64,3,opt,name=dew_point,json=dewPoint,proto3" json:"dew_point,omitempty"`
+	UvIndex       int32                  `protobuf:"varint,4,opt,name=uv_index,json=uvIndex,proto3" json:"uv_index,omitempty"`
+	Visibility    float64                `protobuf:"fixed64,5,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	Units         *Units                 `protobuf:"bytes,6,opt,name=units,proto3" json:"units,omitempty"`
+	Location      *Location              `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metrics) Reset() {
+	*x = Metrics{}
+	mi := &file_zephyr_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metrics) ProtoMessage() {}
+
+func (x *Metrics) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metrics.ProtoReflect.Descriptor instead.
+func (*Metrics) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Metrics) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *Metrics) GetPressure() int32 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *Metrics) GetDewPoint() float64 {
+	if x != nil {
+		return x.DewPoint
+	}
+	return 0
+}
+
+func (x *Metrics) GetUvIndex() int32 {
+	if x != nil {
+		return x.UvIndex
+	}
+	return 0
+}
+
+func (x *Metrics) GetVisibility() float64 {
+	if x != nil {
+		return x.Visibility
+	}
+	return 0
+}
+
+func (x *Metrics) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *Metrics) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type Wind struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Arrow         string                 `protobuf:"bytes,1,opt,name=arrow,proto3" json:"arrow,omitempty"`
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	Speed         float64                `protobuf:"fixed64,3,opt,name=speed,proto3" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Wind) Reset() {
+	*x = Wind{}
+	mi := &file_zephyr_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Wind) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wind) ProtoMessage() {}
+
+func (x *Wind) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wind.ProtoReflect.Descriptor instead.
+func (*Wind) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Wind) GetArrow() string {
+	if x != nil {
+		return x.Arrow
+	}
+	return ""
+}
+
+func (x *Wind) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Wind) GetSpeed() float64 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+type CurrentWind struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wind          *Wind                  `protobuf:"bytes,1,opt,name=wind,proto3" json:"wind,omitempty"`
+	Units         *Units                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	Location      *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrentWind) Reset() {
+	*x = CurrentWind{}
+	mi := &file_zephyr_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrentWind) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrentWind) ProtoMessage() {}
+
+func (x *CurrentWind) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrentWind.ProtoReflect.Descriptor instead.
+func (*CurrentWind) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CurrentWind) GetWind() *Wind {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+func (x *CurrentWind) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *CurrentWind) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type ForecastEntity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Min           float64                `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	Condition     string                 `protobuf:"bytes,4,opt,name=condition,proto3" json:"condition,omitempty"`
+	Emoji         string                 `protobuf:"bytes,5,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	FeelsLike     float64                `protobuf:"fixed64,6,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	Wind          *Wind                  `protobuf:"bytes,7,opt,name=wind,proto3" json:"wind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForecastEntity) Reset() {
+	*x = ForecastEntity{}
+	mi := &file_zephyr_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastEntity) ProtoMessage() {}
+
+func (x *ForecastEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastEntity.ProtoReflect.Descriptor instead.
+func (*ForecastEntity) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ForecastEntity) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *ForecastEntity) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ForecastEntity) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *ForecastEntity) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *ForecastEntity) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ForecastEntity) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *ForecastEntity) GetWind() *Wind {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+type Forecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Forecast      []*ForecastEntity      `protobuf:"bytes,1,rep,name=forecast,proto3" json:"forecast,omitempty"`
+	Units         *Units                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	Location      *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Forecast) Reset() {
+	*x = Forecast{}
+	mi := &file_zephyr_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Forecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Forecast) ProtoMessage() {}
+
+func (x *Forecast) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Forecast.ProtoReflect.Descriptor instead.
+func (*Forecast) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Forecast) GetForecast() []*ForecastEntity {
+	if x != nil {
+		return x.Forecast
+	}
+	return nil
+}
+
+func (x *Forecast) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *Forecast) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type Moon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Icon          string                 `protobuf:"bytes,1,opt,name=icon,proto3" json:"icon,omitempty"`
+	Phase         string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Percentage    int32                  `protobuf:"varint,3,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Moon) Reset() {
+	*x = Moon{}
+	mi := &file_zephyr_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Moon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Moon) ProtoMessage() {}
+
+func (x *Moon) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Moon.ProtoReflect.Descriptor instead.
+func (*Moon) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Moon) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Moon) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *Moon) GetPercentage() int32 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+type WeatherAnomaly struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Temperature   float64                `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherAnomaly) Reset() {
+	*x = WeatherAnomaly{}
+	mi := &file_zephyr_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherAnomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherAnomaly) ProtoMessage() {}
+
+func (x *WeatherAnomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherAnomaly.ProtoReflect.Descriptor instead.
+func (*WeatherAnomaly) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WeatherAnomaly) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *WeatherAnomaly) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+type Statistics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float64                `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Mean          float64                `protobuf:"fixed64,4,opt,name=mean,proto3" json:"mean,omitempty"`
+	StdDev        float64                `protobuf:"fixed64,5,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	Median        float64                `protobuf:"fixed64,6,opt,name=median,proto3" json:"median,omitempty"`
+	Mode          float64                `protobuf:"fixed64,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	Anomalies     []*WeatherAnomaly      `protobuf:"bytes,8,rep,name=anomalies,proto3" json:"anomalies,omitempty"`
+	Units         *Units                 `protobuf:"bytes,9,opt,name=units,proto3" json:"units,omitempty"`
+	Location      *Location              `protobuf:"bytes,10,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_zephyr_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_zephyr_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_zephyr_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Statistics) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Statistics) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Statistics) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Statistics) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Statistics) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *Statistics) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *Statistics) GetMode() float64 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *Statistics) GetAnomalies() []*WeatherAnomaly {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+func (x *Statistics) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *Statistics) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+var File_zephyr_proto protoreflect.FileDescriptor
+
+const file_zephyr_proto_rawDesc = "" +
+	"\n" +
+	"\fzephyr.proto\x12\tzephyr.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"1\n" +
+	"\vCoordinates\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\"\xc7\x01\n" +
+	"\x0fLocationRequest\x12\x14\n" +
+	"\x04city\x18\x01 \x01(\tH\x00R\x04city\x12:\n" +
+	"\vcoordinates\x18\x02 \x01(\v2\x16.zephyr.v1.CoordinatesH\x00R\vcoordinates\x12\x12\n" +
+	"\x03zip\x18\x03 \x01(\tH\x00R\x03zip\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12+\n" +
+	"\x05units\x18\x05 \x01(\x0e2\x15.zephyr.v1.UnitSystemR\x05unitsB\a\n" +
+	"\x05query\"\r\n" +
+	"\vMoonRequest\"w\n" +
+	"\x05Units\x12 \n" +
+	"\vtemperature\x18\x01 \x01(\tR\vtemperature\x12\x14\n" +
+	"\x05speed\x18\x02 \x01(\tR\x05speed\x12\x1a\n" +
+	"\bpressure\x18\x03 \x01(\tR\bpressure\x12\x1a\n" +
+	"\bdistance\x18\x04 \x01(\tR\bdistance\"r\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x10\n" +
+	"\x03lat\x18\x04 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x05 \x01(\x01R\x03lon\"\x87\x02\n" +
+	"\aWeather\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x04 \x01(\x01R\tfeelsLike\x12\x14\n" +
+	"\x05emoji\x18\x05 \x01(\tR\x05emoji\x12&\n" +
+	"\x05units\x18\x06 \x01(\v2\x10.zephyr.v1.UnitsR\x05units\x12/\n" +
+	"\blocation\x18\a \x01(\v2\x13.zephyr.v1.LocationR\blocation\"\xf2\x01\n" +
+	"\aMetrics\x12\x1a\n" +
+	"\bhumidity\x18\x01 \x01(\x05R\bhumidity\x12\x1a\n" +
+	"\bpressure\x18\x02 \x01(\x05R\bpressure\x12\x1b\n" +
+	"\tdew_point\x18\x03 \x01(\x01R\bdewPoint\x12\x19\n" +
+	"\buv_index\x18\x04 \x01(\x05R\auvIndex\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x05 \x01(\x01R\n" +
+	"visibility\x12&\n" +
+	"\x05units\x18\x06 \x01(\v2\x10.zephyr.v1.UnitsR\x05units\x12/\n" +
+	"\blocation\x18\a \x01(\v2\x13.zephyr.v1.LocationR\blocation\"P\n" +
+	"\x04Wind\x12\x14\n" +
+	"\x05arrow\x18\x01 \x01(\tR\x05arrow\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05speed\x18\x03 \x01(\x01R\x05speed\"\x8b\x01\n" +
+	"\vCurrentWind\x12#\n" +
+	"\x04wind\x18\x01 \x01(\v2\x0f.zephyr.v1.WindR\x04wind\x12&\n" +
+	"\x05units\x18\x02 \x01(\v2\x10.zephyr.v1.UnitsR\x05units\x12/\n" +
+	"\blocation\x18\x03 \x01(\v2\x13.zephyr.v1.LocationR\blocation\"\xdc\x01\n" +
+	"\x0eForecastEntity\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12\x1c\n" +
+	"\tcondition\x18\x04 \x01(\tR\tcondition\x12\x14\n" +
+	"\x05emoji\x18\x05 \x01(\tR\x05emoji\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x06 \x01(\x01R\tfeelsLike\x12#\n" +
+	"\x04wind\x18\a \x01(\v2\x0f.zephyr.v1.WindR\x04wind\"\x9a\x01\n" +
+	"\bForecast\x125\n" +
+	"\bforecast\x18\x01 \x03(\v2\x19.zephyr.v1.ForecastEntityR\bforecast\x12&\n" +
+	"\x05units\x18\x02 \x01(\v2\x10.zephyr.v1.UnitsR\x05units\x12/\n" +
+	"\blocation\x18\x03 \x01(\v2\x13.zephyr.v1.LocationR\blocation\"P\n" +
+	"\x04Moon\x12\x12\n" +
+	"\x04icon\x18\x01 \x01(\tR\x04icon\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x03 \x01(\x05R\n" +
+	"percentage\"b\n" +
+	"\x0eWeatherAnomaly\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\"\xb1\x02\n" +
+	"\n" +
+	"Statistics\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x01R\x03max\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x12\x12\n" +
+	"\x04mean\x18\x04 \x01(\x01R\x04mean\x12\x17\n" +
+	"\astd_dev\x18\x05 \x01(\x01R\x06stdDev\x12\x16\n" +
+	"\x06median\x18\x06 \x01(\x01R\x06median\x12\x12\n" +
+	"\x04mode\x18\a \x01(\x01R\x04mode\x127\n" +
+	"\tanomalies\x18\b \x03(\v2\x19.zephyr.v1.WeatherAnomalyR\tanomalies\x12&\n" +
+	"\x05units\x18\t \x01(\v2\x10.zephyr.v1.UnitsR\x05units\x12/\n" +
+	"\blocation\x18\n" +
+	" \x01(\v2\x13.zephyr.v1.LocationR\blocation*>\n" +
+	"\n" +
+	"UnitSystem\x12\x16\n" +
+	"\x12UNIT_SYSTEM_METRIC\x10\x00\x12\x18\n" +
+	"\x14UNIT_SYSTEM_IMPERIAL\x10\x012\xbd\x03\n" +
+	"\x06Zephyr\x12<\n" +
+	"\n" +
+	"GetWeather\x12\x1a.zephyr.v1.LocationRequest\x1a\x12.zephyr.v1.Weather\x12<\n" +
+	"\n" +
+	"GetMetrics\x12\x1a.zephyr.v1.LocationRequest\x1a\x12.zephyr.v1.Metrics\x12=\n" +
+	"\aGetWind\x12\x1a.zephyr.v1.LocationRequest\x1a\x16.zephyr.v1.CurrentWind\x12>\n" +
+	"\vGetForecast\x12\x1a.zephyr.v1.LocationRequest\x1a\x13.zephyr.v1.Forecast\x122\n" +
+	"\aGetMoon\x12\x16.zephyr.v1.MoonRequest\x1a\x0f.zephyr.v1.Moon\x12B\n" +
+	"\rGetStatistics\x12\x1a.zephyr.v1.LocationRequest\x1a\x15.zephyr.v1.Statistics\x12@\n" +
+	"\fWatchWeather\x12\x1a.zephyr.v1.LocationRequest\x1a\x12.zephyr.v1.Weather0\x01B#Z!github.com/ceticamarco/zephyr/rpcb\x06proto3"
+
+var (
+	file_zephyr_proto_rawDescOnce sync.Once
+	file_zephyr_proto_rawDescData []byte
+)
+
+func file_zephyr_proto_rawDescGZIP() []byte {
+	file_zephyr_proto_rawDescOnce.Do(func() {
+		file_zephyr_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_zephyr_proto_rawDesc), len(file_zephyr_proto_rawDesc)))
+	})
+	return file_zephyr_proto_rawDescData
+}
+
+var file_zephyr_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_zephyr_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_zephyr_proto_goTypes = []any{
+	(UnitSystem)(0),               // 0: zephyr.v1.UnitSystem
+	(*Coordinates)(nil),           // 1: zephyr.v1.Coordinates
+	(*LocationRequest)(nil),       // 2: zephyr.v1.LocationRequest
+	(*MoonRequest)(nil),           // 3: zephyr.v1.MoonRequest
+	(*Units)(nil),                 // 4: zephyr.v1.Units
+	(*Location)(nil),              // 5: zephyr.v1.Location
+	(*Weather)(nil),               // 6: zephyr.v1.Weather
+	(*Metrics)(nil),               // 7: zephyr.v1.Metrics
+	(*Wind)(nil),                  // 8: zephyr.v1.Wind
+	(*CurrentWind)(nil),           // 9: zephyr.v1.CurrentWind
+	(*ForecastEntity)(nil),        // 10: zephyr.v1.ForecastEntity
+	(*Forecast)(nil),              // 11: zephyr.v1.Forecast
+	(*Moon)(nil),                  // 12: zephyr.v1.Moon
+	(*WeatherAnomaly)(nil),        // 13: zephyr.v1.WeatherAnomaly
+	(*Statistics)(nil),            // 14: zephyr.v1.Statistics
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_zephyr_proto_depIdxs = []int32{
+	1,  // 0: zephyr.v1.LocationRequest.coordinates:type_name -> zephyr.v1.Coordinates
+	0,  // 1: zephyr.v1.LocationRequest.units:type_name -> zephyr.v1.UnitSystem
+	15, // 2: zephyr.v1.Weather.date:type_name -> google.protobuf.Timestamp
+	4,  // 3: zephyr.v1.Weather.units:type_name -> zephyr.v1.Units
+	5,  // 4: zephyr.v1.Weather.location:type_name -> zephyr.v1.Location
+	4,  // 5: zephyr.v1.Metrics.units:type_name -> zephyr.v1.Units
+	5,  // 6: zephyr.v1.Metrics.location:type_name -> zephyr.v1.Location
+	8,  // 7: zephyr.v1.CurrentWind.wind:type_name -> zephyr.v1.Wind
+	4,  // 8: zephyr.v1.CurrentWind.units:type_name -> zephyr.v1.Units
+	5,  // 9: zephyr.v1.CurrentWind.location:type_name -> zephyr.v1.Location
+	15, // 10: zephyr.v1.ForecastEntity.date:type_name -> google.protobuf.Timestamp
+	8,  // 11: zephyr.v1.ForecastEntity.wind:type_name -> zephyr.v1.Wind
+	10, // 12: zephyr.v1.Forecast.forecast:type_name -> zephyr.v1.ForecastEntity
+	4,  // 13: zephyr.v1.Forecast.units:type_name -> zephyr.v1.Units
+	5,  // 14: zephyr.v1.Forecast.location:type_name -> zephyr.v1.Location
+	15, // 15: zephyr.v1.WeatherAnomaly.date:type_name -> google.protobuf.Timestamp
+	13, // 16: zephyr.v1.Statistics.anomalies:type_name -> zephyr.v1.WeatherAnomaly
+	4,  // 17: zephyr.v1.Statistics.units:type_name -> zephyr.v1.Units
+	5,  // 18: zephyr.v1.Statistics.location:type_name -> zephyr.v1.Location
+	2,  // 19: zephyr.v1.Zephyr.GetWeather:input_type -> zephyr.v1.LocationRequest
+	2,  // 20: zephyr.v1.Zephyr.GetMetrics:input_type -> zephyr.v1.LocationRequest
+	2,  // 21: zephyr.v1.Zephyr.GetWind:input_type -> zephyr.v1.LocationRequest
+	2,  // 22: zephyr.v1.Zephyr.GetForecast:input_type -> zephyr.v1.LocationRequest
+	3,  // 23: zephyr.v1.Zephyr.GetMoon:input_type -> zephyr.v1.MoonRequest
+	2,  // 24: zephyr.v1.Zephyr.GetStatistics:input_type -> zephyr.v1.LocationRequest
+	2,  // 25: zephyr.v1.Zephyr.WatchWeather:input_type -> zephyr.v1.LocationRequest
+	6,  // 26: zephyr.v1.Zephyr.GetWeather:output_type -> zephyr.v1.Weather
+	7,  // 27: zephyr.v1.Zephyr.GetMetrics:output_type -> zephyr.v1.Metrics
+	9,  // 28: zephyr.v1.Zephyr.GetWind:output_type -> zephyr.v1.CurrentWind
+	11, // 29: zephyr.v1.Zephyr.GetForecast:output_type -> zephyr.v1.Forecast
+	12, // 30: zephyr.v1.Zephyr.GetMoon:output_type -> zephyr.v1.Moon
+	14, // 31: zephyr.v1.Zephyr.GetStatistics:output_type -> zephyr.v1.Statistics
+	6,  // 32: zephyr.v1.Zephyr.WatchWeather:output_type -> zephyr.v1.Weather
+	26, // [26:33] is the sub-list for method output_type
+	19, // [19:26] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_zephyr_proto_init() }
+func file_zephyr_proto_init() {
+	if File_zephyr_proto != nil {
+		return
+	}
+	file_zephyr_proto_msgTypes[1].OneofWrappers = []any{
+		(*LocationRequest_City)(nil),
+		(*LocationRequest_Coordinates)(nil),
+		(*LocationRequest_Zip)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_zephyr_proto_rawDesc), len(file_zephyr_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_zephyr_proto_goTypes,
+		DependencyIndexes: file_zephyr_proto_depIdxs,
+		EnumInfos:         file_zephyr_proto_enumTypes,
+		MessageInfos:      file_zephyr_proto_msgTypes,
+	}.Build()
+	File_zephyr_proto = out.File
+	file_zephyr_proto_goTypes = nil
+	file_zephyr_proto_depIdxs = nil
+}
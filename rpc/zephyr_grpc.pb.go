@@ -0,0 +1,361 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: zephyr.proto
+
+// Weather data served over gRPC, backed by the same caches and
+// statistical database of the HTTP API. Measures are numbers
+// expressed in the units listed by each message, as in the '/v2/' API
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Zephyr_GetWeather_FullMethodName    = "/zephyr.v1.Zephyr/GetWeather"
+	Zephyr_GetMetrics_FullMethodName    = "/zephyr.v1.Zephyr/GetMetrics"
+	Zephyr_GetWind_FullMethodName       = "/zephyr.v1.Zephyr/GetWind"
+	Zephyr_GetForecast_FullMethodName   = "/zephyr.v1.Zephyr/GetForecast"
+	Zephyr_GetMoon_FullMethodName       = "/zephyr.v1.Zephyr/GetMoon"
+	Zephyr_GetStatistics_FullMethodName = "/zephyr.v1.Zephyr/GetStatistics"
+	Zephyr_WatchWeather_FullMethodName  = "/zephyr.v1.Zephyr/WatchWeather"
+)
+
+// ZephyrClient is the client API for Zephyr service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ZephyrClient interface {
+	GetWeather(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Weather, error)
+	GetMetrics(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Metrics, error)
+	GetWind(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*CurrentWind, error)
+	GetForecast(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Forecast, error)
+	GetMoon(ctx context.Context, in *MoonRequest, opts ...grpc.CallOption) (*Moon, error)
+	GetStatistics(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Statistics, error)
+	// Sends the weather of a location, and then sends it
+	// again whenever a newer observation is available
+	WatchWeather(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Weather], error)
+}
+
+type zephyrClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewZephyrClient(cc grpc.ClientConnInterface) ZephyrClient {
+	return &zephyrClient{cc}
+}
+
+func (c *zephyrClient) GetWeather(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Weather, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Weather)
+	err := c.cc.Invoke(ctx, Zephyr_GetWeather_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) GetMetrics(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Metrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Metrics)
+	err := c.cc.Invoke(ctx, Zephyr_GetMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) GetWind(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*CurrentWind, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CurrentWind)
+	err := c.cc.Invoke(ctx, Zephyr_GetWind_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) GetForecast(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Forecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Forecast)
+	err := c.cc.Invoke(ctx, Zephyr_GetForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) GetMoon(ctx context.Context, in *MoonRequest, opts ...grpc.CallOption) (*Moon, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Moon)
+	err := c.cc.Invoke(ctx, Zephyr_GetMoon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) GetStatistics(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (*Statistics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Statistics)
+	err := c.cc.Invoke(ctx, Zephyr_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zephyrClient) WatchWeather(ctx context.Context, in *LocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Weather], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Zephyr_ServiceDesc.Streams[0], Zephyr_WatchWeather_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LocationRequest, Weather]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Zephyr_WatchWeatherClient = grpc.ServerStreamingClient[Weather]
+
+// ZephyrServer is the server API for Zephyr service.
+// All implementations must embed UnimplementedZephyrServer
+// for forward compatibility.
+type ZephyrServer interface {
+	GetWeather(context.Context, *LocationRequest) (*Weather, error)
+	GetMetrics(context.Context, *LocationRequest) (*Metrics, error)
+	GetWind(context.Context, *LocationRequest) (*CurrentWind, error)
+	GetForecast(context.Context, *LocationRequest) (*Forecast, error)
+	GetMoon(context.Context, *MoonRequest) (*Moon, error)
+	GetStatistics(context.Context, *LocationRequest) (*Statistics, error)
+	// Sends the weather of a location, and then sends it
+	// again whenever a newer observation is available
+	WatchWeather(*LocationRequest, grpc.ServerStreamingServer[Weather]) error
+	mustEmbedUnimplementedZephyrServer()
+}
+
+// UnimplementedZephyrServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedZephyrServer struct{}
+
+func (UnimplementedZephyrServer) GetWeather(context.Context, *LocationRequest) (*Weather, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetWeather not implemented")
+}
+func (UnimplementedZephyrServer) GetMetrics(context.Context, *LocationRequest) (*Metrics, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMetrics not implemented")
+}
+func (UnimplementedZephyrServer) GetWind(context.Context, *LocationRequest) (*CurrentWind, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetWind not implemented")
+}
+func (UnimplementedZephyrServer) GetForecast(context.Context, *LocationRequest) (*Forecast, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetForecast not implemented")
+}
+func (UnimplementedZephyrServer) GetMoon(context.Context, *MoonRequest) (*Moon, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMoon not implemented")
+}
+func (UnimplementedZephyrServer) GetStatistics(context.Context, *LocationRequest) (*Statistics, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedZephyrServer) WatchWeather(*LocationRequest, grpc.ServerStreamingServer[Weather]) error {
+	return status.Error(codes.Unimplemented, "method WatchWeather not implemented")
+}
+func (UnimplementedZephyrServer) mustEmbedUnimplementedZephyrServer() {}
+func (UnimplementedZephyrServer) testEmbeddedByValue()                {}
+
+// UnsafeZephyrServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ZephyrServer will
+// result in compilation errors.
+type UnsafeZephyrServer interface {
+	mustEmbedUnimplementedZephyrServer()
+}
+
+func RegisterZephyrServer(s grpc.ServiceRegistrar, srv ZephyrServer) {
+	// If the following call panics, it indicates UnimplementedZephyrServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Zephyr_ServiceDesc, srv)
+}
+
+func _Zephyr_GetWeather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetWeather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetWeather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetWeather(ctx, req.(*LocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetMetrics(ctx, req.(*LocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_GetWind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetWind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetWind_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetWind(ctx, req.(*LocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetForecast(ctx, req.(*LocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_GetMoon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetMoon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetMoon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetMoon(ctx, req.(*MoonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZephyrServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Zephyr_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZephyrServer).GetStatistics(ctx, req.(*LocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zephyr_WatchWeather_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LocationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ZephyrServer).WatchWeather(m, &grpc.GenericServerStream[LocationRequest, Weather]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Zephyr_WatchWeatherServer = grpc.ServerStreamingServer[Weather]
+
+// Zephyr_ServiceDesc is the grpc.ServiceDesc for Zephyr service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Zephyr_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zephyr.v1.Zephyr",
+	HandlerType: (*ZephyrServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWeather",
+			Handler:    _Zephyr_GetWeather_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _Zephyr_GetMetrics_Handler,
+		},
+		{
+			MethodName: "GetWind",
+			Handler:    _Zephyr_GetWind_Handler,
+		},
+		{
+			MethodName: "GetForecast",
+			Handler:    _Zephyr_GetForecast_Handler,
+		},
+		{
+			MethodName: "GetMoon",
+			Handler:    _Zephyr_GetMoon_Handler,
+		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _Zephyr_GetStatistics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchWeather",
+			Handler:       _Zephyr_WatchWeather_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "zephyr.proto",
+}
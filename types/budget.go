@@ -0,0 +1,176 @@
+package types
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Budget data type, representing the number of upstream calls
+// performed within the current UTC day
+type Budget struct {
+	mu        sync.Mutex
+	saveMu    sync.Mutex // Serializes writes of the budget file
+	path      string
+	day       string
+	calls     uint
+	isDirty   bool // Whether the usage changed since the last save
+	softLimit uint
+	hardLimit uint
+}
+
+// BudgetUsage, representing a snapshot of the daily budget
+type BudgetUsage struct {
+	Day       string `json:"day"`
+	Calls     uint   `json:"calls"`
+	Remaining uint   `json:"remaining"`
+	SoftLimit uint   `json:"softLimit"`
+	HardLimit uint   `json:"hardLimit"`
+	Throttled bool   `json:"throttled"`
+}
+
+// Structure representing the budget file
+type budgetFile struct {
+	Day   string `json:"day"`
+	Calls uint   `json:"calls"`
+}
+
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// InitBudget initializes the daily budget, restoring the usage of the
+// current day from the given file(if any)
+func InitBudget(path string, softLimit uint, hardLimit uint) (*Budget, error) {
+	budget := &Budget{
+		path:      path,
+		day:       today(),
+		softLimit: softLimit,
+		hardLimit: hardLimit,
+	}
+
+	if path == "" {
+		return budget, nil
+	}
+
+	content, err := readFile(path)
+	if err != nil {
+		return nil, err
+	} else if content == nil {
+		return budget, nil
+	}
+
+	var stored budgetFile
+	if err := json.Unmarshal(content, &stored); err != nil {
+		return nil, err
+	}
+
+	// Discard usage of previous days
+	if stored.Day == budget.day {
+		budget.calls = stored.Calls
+	}
+
+	return budget, nil
+}
+
+func (budget *Budget) rollover() {
+	if day := today(); day != budget.day {
+		budget.day = day
+		budget.calls = 0
+		budget.isDirty = true
+	}
+}
+
+// Save writes the usage of the current day to the budget file, if it changed
+// since the last save. Upstream calls only update the usage in memory, hence
+// the file is meant to be written periodically and on shutdown
+func (budget *Budget) Save() error {
+	if budget.path == "" {
+		return nil
+	}
+
+	budget.saveMu.Lock()
+	defer budget.saveMu.Unlock()
+
+	budget.mu.Lock()
+	budget.rollover()
+	stored := budgetFile{Day: budget.day, Calls: budget.calls}
+	isDirty := budget.isDirty
+	budget.isDirty = false
+	budget.mu.Unlock()
+
+	if !isDirty {
+		return nil
+	}
+
+	content, err := json.Marshal(stored)
+	if err == nil {
+		err = writeFile(budget.path, content, 0o644)
+	}
+
+	// Try again on the next save
+	if err != nil {
+		budget.mu.Lock()
+		budget.isDirty = true
+		budget.mu.Unlock()
+	}
+
+	return err
+}
+
+// Acquire reserves an upstream call, returning false
+// if the hard limit has been reached
+func (budget *Budget) Acquire() bool {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.rollover()
+	if budget.calls >= budget.hardLimit {
+		return false
+	}
+
+	budget.calls++
+	budget.isDirty = true
+
+	return true
+}
+
+// SetLimits changes the daily limits without resetting the usage
+func (budget *Budget) SetLimits(softLimit uint, hardLimit uint) {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.softLimit = softLimit
+	budget.hardLimit = hardLimit
+}
+
+// IsThrottled reports whether the soft limit has been reached
+func (budget *Budget) IsThrottled() bool {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.rollover()
+
+	return budget.calls >= budget.softLimit
+}
+
+func (budget *Budget) Usage() BudgetUsage {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.rollover()
+
+	var remaining uint
+	if budget.calls < budget.hardLimit {
+		remaining = budget.hardLimit - budget.calls
+	}
+
+	return BudgetUsage{
+		Day:       budget.day,
+		Calls:     budget.calls,
+		Remaining: remaining,
+		SoftLimit: budget.softLimit,
+		HardLimit: budget.hardLimit,
+		Throttled: budget.calls >= budget.softLimit,
+	}
+}
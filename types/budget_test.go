@@ -0,0 +1,61 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBudgetLimits(t *testing.T) {
+	budget, err := InitBudget("", 1, 2)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	tests := []struct {
+		Name      string
+		Acquired  bool
+		Throttled bool
+	}{
+		{"Below soft limit", true, true},
+		{"Below hard limit", true, true},
+		{"Hard limit reached", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			acquired := budget.Acquire()
+
+			if acquired != test.Acquired || budget.IsThrottled() != test.Throttled {
+				t.Errorf("Got (%v, %v), wanted (%v, %v)", acquired, budget.IsThrottled(), test.Acquired, test.Throttled)
+			}
+		})
+	}
+}
+
+func TestBudgetPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+
+	budget, _ := InitBudget(path, 900, 1000)
+	budget.Acquire()
+	budget.Acquire()
+
+	// Upstream calls are only counted in memory until the next save
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Got %v, wanted the budget file not to be written yet", err)
+	}
+
+	if err := budget.Save(); err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	// Usage of the current day must survive restarts
+	restored, err := InitBudget(path, 900, 1000)
+	if err != nil {
+		t.Fatalf("Got error %v", err)
+	}
+
+	if got := restored.Usage(); got.Calls != 2 || got.Remaining != 998 {
+		t.Errorf("Got %+v, wanted 2 calls and 998 remaining", got)
+	}
+}
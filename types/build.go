@@ -0,0 +1,44 @@
+package types
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// BuildInfo data type, representing the version of the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"goVersion"`
+}
+
+// ReadBuildInfo returns the build information of the running binary. Values
+// injected at link time take precedence over the ones recorded by the Go toolchain
+func ReadBuildInfo(version string, commit string) BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, isPresent := debug.ReadBuildInfo(); isPresent {
+		if info.Version == "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" && info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+
+	return info
+}
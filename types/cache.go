@@ -1,24 +1,71 @@
 package types
 
 import (
+	"context"
+	"encoding/json"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// cacheType, representing the abstract value of a CacheEntity
-type cacheType interface {
-	Weather | Metrics | Wind | Forecast | Moon
+// CacheType, representing the abstract value of a CacheEntity
+type CacheType interface {
+	Weather | Metrics | Wind | Forecast | Moon | Candidates
 }
 
 // CacheEntity, representing the value of the cache
-type CacheEntity[T cacheType] struct {
+type CacheEntity[T CacheType] struct {
 	element   T
 	timestamp time.Time
 }
 
+// CacheStats, representing the usage counters of a cache
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// CacheEntryInfo, representing the metadata of a single cache entry
+type CacheEntryInfo struct {
+	Key       string    `json:"key"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Age       string    `json:"age"`
+	TTL       string    `json:"ttl"`
+	Expired   bool      `json:"expired"`
+}
+
+// CacheInspector, representing the operations shared by every cache
+// regardless of the type of its values
+type CacheInspector interface {
+	Entries(ttl time.Duration) []CacheEntryInfo
+	Stats() CacheStats
+	Purge() int
+	PurgeKey(key string) bool
+	Sweep(maxAge time.Duration) int
+	Snapshot() (json.RawMessage, error)
+	Restore(snapshot json.RawMessage) error
+}
+
 // Cache, representing a mapping between a key(str) and a CacheEntity
-type Cache[T cacheType] struct {
-	Data map[string]CacheEntity[T]
+type Cache[T CacheType] struct {
+	mu        sync.RWMutex
+	data      map[string]CacheEntity[T]
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	flightMu  sync.Mutex
+	flights   map[string]*flight[T]
+}
+
+// flight, representing a fetch shared by the concurrent requests of a key
+type flight[T CacheType] struct {
+	done      chan struct{}
+	value     T
+	fetchedAt time.Time
+	err       error
 }
 
 // Caches, representing a grouping of the various caches
@@ -27,65 +74,220 @@ type Caches struct {
 	MetricsCache  Cache[Metrics]
 	WindCache     Cache[Wind]
 	ForecastCache Cache[Forecast]
-	MoonCache     CacheEntity[Moon]
+	MoonCache     Cache[Moon]
+	GeoCache      Cache[Candidates]
 }
 
+// Expired entries are kept for an additional amount of time, so that they
+// can be served when the upstream provider is not available
+const StaleWindow = 24 * time.Hour
+
+// The moon phase does not depend on the location,
+// thus the moon cache holds a single entry
+const MoonKey = "MOON"
+
 func InitCache() *Caches {
 	return &Caches{
-		WeatherCache:  Cache[Weather]{Data: make(map[string]CacheEntity[Weather])},
-		MetricsCache:  Cache[Metrics]{Data: make(map[string]CacheEntity[Metrics])},
-		WindCache:     Cache[Wind]{Data: make(map[string]CacheEntity[Wind])},
-		ForecastCache: Cache[Forecast]{Data: make(map[string]CacheEntity[Forecast])},
-		MoonCache:     CacheEntity[Moon]{element: Moon{}, timestamp: time.Time{}},
+		WeatherCache:  Cache[Weather]{data: make(map[string]CacheEntity[Weather])},
+		MetricsCache:  Cache[Metrics]{data: make(map[string]CacheEntity[Metrics])},
+		WindCache:     Cache[Wind]{data: make(map[string]CacheEntity[Wind])},
+		ForecastCache: Cache[Forecast]{data: make(map[string]CacheEntity[Forecast])},
+		MoonCache:     Cache[Moon]{data: make(map[string]CacheEntity[Moon])},
+		GeoCache:      Cache[Candidates]{data: make(map[string]CacheEntity[Candidates])},
 	}
 }
 
-func (cache *Cache[T]) GetEntry(cityName string, ttl int8) (T, bool) {
-	val, isPresent := cache.Data[strings.ToUpper(cityName)]
+func (cache *Cache[T]) GetEntry(cityName string, ttl time.Duration) (T, bool) {
+	element, _, found := cache.GetTimedEntry(cityName, ttl)
+
+	return element, found
+}
+
+// GetTimedEntry returns the value of a key along with the time it has been fetched
+func (cache *Cache[T]) GetTimedEntry(cityName string, ttl time.Duration) (T, time.Time, bool) {
+	cache.mu.RLock()
+	val, isPresent := cache.data[strings.ToUpper(cityName)]
+	cache.mu.RUnlock()
 
 	// If key is not present, return a zero value
 	if !isPresent {
-		return val.element, false
+		cache.misses.Add(1)
+		return val.element, val.timestamp, false
 	}
 
 	// Otherwise check whether cache element is expired
 	currentTime := time.Now()
-	expired := currentTime.Sub(val.timestamp) > (time.Duration(ttl) * time.Hour)
+	expired := currentTime.Sub(val.timestamp) > ttl
 	if expired {
-		return val.element, false
+		cache.misses.Add(1)
+		return val.element, val.timestamp, false
 	}
 
-	return val.element, true
+	cache.hits.Add(1)
+	return val.element, val.timestamp, true
 }
 
-func (cache *Cache[T]) AddEntry(entry T, cityName string) {
+// GetStaleEntry returns the value of a key regardless of its expiration,
+// as long as it is not older than maxAge, along with the time it has been fetched
+func (cache *Cache[T]) GetStaleEntry(cityName string, maxAge time.Duration) (T, time.Time, bool) {
+	cache.mu.RLock()
+	val, isPresent := cache.data[strings.ToUpper(cityName)]
+	cache.mu.RUnlock()
+
+	if !isPresent || time.Since(val.timestamp) > maxAge {
+		return val.element, val.timestamp, false
+	}
+
+	return val.element, val.timestamp, true
+}
+
+// AddEntry caches a value, returning the time it has been added
+func (cache *Cache[T]) AddEntry(entry T, cityName string) time.Time {
 	currentTime := time.Now()
 
-	cache.Data[strings.ToUpper(cityName)] = CacheEntity[T]{
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.data[strings.ToUpper(cityName)] = CacheEntity[T]{
 		element:   entry,
 		timestamp: currentTime,
 	}
+
+	return currentTime
+}
+
+// Coalesce fetches and caches the value of a key, sharing a single fetch
+// among the concurrent callers of the same key. The fetch is detached from
+// the context of the caller that started it, hence a caller giving up(e.g. a
+// disconnected client) neither cancels it nor fails the other callers
+func (cache *Cache[T]) Coalesce(ctx context.Context, cityName string, fetch func(ctx context.Context) (T, error)) (T, time.Time, error) {
+	key := strings.ToUpper(cityName)
+
+	cache.flightMu.Lock()
+	pending, isPresent := cache.flights[key]
+	if !isPresent {
+		if cache.flights == nil {
+			cache.flights = make(map[string]*flight[T])
+		}
+		pending = &flight[T]{done: make(chan struct{})}
+		cache.flights[key] = pending
+
+		go func() {
+			pending.value, pending.err = fetch(context.WithoutCancel(ctx))
+			if pending.err == nil {
+				pending.fetchedAt = cache.AddEntry(pending.value, key)
+			}
+
+			cache.flightMu.Lock()
+			delete(cache.flights, key)
+			cache.flightMu.Unlock()
+			close(pending.done)
+		}()
+	}
+	cache.flightMu.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.value, pending.fetchedAt, pending.err
+	case <-ctx.Done():
+		var zero T
+		return zero, time.Time{}, ctx.Err()
+	}
+}
+
+func (cache *Cache[T]) Entries(ttl time.Duration) []CacheEntryInfo {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	currentTime := time.Now()
+	entries := make([]CacheEntryInfo, 0, len(cache.data))
+	for key, val := range cache.data {
+		age := currentTime.Sub(val.timestamp)
+
+		entries = append(entries, CacheEntryInfo{
+			Key:       key,
+			FetchedAt: val.timestamp,
+			Age:       age.Round(time.Second).String(),
+			TTL:       ttl.String(),
+			Expired:   age > ttl,
+		})
+	}
+
+	// Sort entries by key to provide a stable output
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+func (cache *Cache[T]) Stats() CacheStats {
+	return CacheStats{
+		Hits:      cache.hits.Load(),
+		Misses:    cache.misses.Load(),
+		Evictions: cache.evictions.Load(),
+	}
 }
 
-func (moon *CacheEntity[Moon]) GetEntry(ttl int8) (Moon, bool) {
-	var zeroMoon Moon
+func (cache *Cache[T]) Purge() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	count := len(cache.data)
+	clear(cache.data)
+	cache.evictions.Add(uint64(count))
+
+	return count
+}
 
-	// If moon data is not present, return a zero value
-	if moon == nil {
-		return zeroMoon, false
+func (cache *Cache[T]) PurgeKey(key string) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	key = strings.ToUpper(key)
+	if _, isPresent := cache.data[key]; !isPresent {
+		return false
 	}
 
-	// Otherwise check whether the element is expired
+	delete(cache.data, key)
+	cache.evictions.Add(1)
+
+	return true
+}
+
+// Sweep removes every entry older than maxAge and returns
+// the number of evicted entries
+func (cache *Cache[T]) Sweep(maxAge time.Duration) int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
 	currentTime := time.Now()
-	expired := currentTime.Sub(moon.timestamp) > (time.Duration(ttl) * time.Hour)
-	if expired {
-		return zeroMoon, false
+	count := 0
+	for key, val := range cache.data {
+		if currentTime.Sub(val.timestamp) > maxAge {
+			delete(cache.data, key)
+			count++
+		}
 	}
+	cache.evictions.Add(uint64(count))
 
-	return moon.element, true
+	return count
 }
 
-func (cache *CacheEntity[Moon]) AddEntry(entry Moon) {
-	cache.element = entry
-	cache.timestamp = time.Now()
+// CacheKind, representing a cache along with its time-to-live
+type CacheKind struct {
+	Cache CacheInspector
+	TTL   time.Duration
+}
+
+// Kinds returns every cache indexed by the kind of data it holds
+func (caches *Caches) Kinds(ttl TimeToLive) map[string]CacheKind {
+	return map[string]CacheKind{
+		"weather":   {&caches.WeatherCache, ttl.Weather},
+		"metrics":   {&caches.MetricsCache, ttl.Metrics},
+		"wind":      {&caches.WindCache, ttl.Wind},
+		"forecast":  {&caches.ForecastCache, ttl.Forecast},
+		"moon":      {&caches.MoonCache, ttl.Moon},
+		"geocoding": {&caches.GeoCache, ttl.Geocoding},
+	}
 }
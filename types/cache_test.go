@@ -0,0 +1,179 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheTimeToLive(t *testing.T) {
+	cache := Cache[Wind]{data: make(map[string]CacheEntity[Wind])}
+	cache.AddEntry(Wind{Direction: "N"}, "milan")
+
+	tests := []struct {
+		Name     string
+		TTL      time.Duration
+		Expected bool
+	}{
+		{"Fresh entry", 10 * time.Minute, true},
+		{"Expired entry", time.Nanosecond, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			time.Sleep(time.Millisecond)
+			_, got := cache.GetEntry("MILAN", test.TTL)
+
+			if got != test.Expected {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestCachePurge(t *testing.T) {
+	cache := Cache[Wind]{data: make(map[string]CacheEntity[Wind])}
+	cache.AddEntry(Wind{}, "milan")
+	cache.AddEntry(Wind{}, "rome")
+
+	if !cache.PurgeKey("milan") {
+		t.Errorf("Got false, wanted true")
+	}
+
+	if _, found := cache.GetEntry("MILAN", time.Hour); found {
+		t.Errorf("Purged key is still present")
+	}
+
+	if got := cache.Purge(); got != 1 {
+		t.Errorf("Got %d, wanted 1", got)
+	}
+
+	stats := cache.Stats()
+	if stats.Evictions != 2 || stats.Misses != 1 {
+		t.Errorf("Got %+v, wanted 2 evictions and 1 miss", stats)
+	}
+}
+
+func TestCacheCoalesce(t *testing.T) {
+	cache := Cache[Wind]{data: make(map[string]CacheEntity[Wind])}
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (Wind, error) {
+		calls.Add(1)
+		<-release
+
+		return Wind{Direction: "N"}, nil
+	}
+
+	// Every request waits for the first fetch, which is released
+	// once the other requests had the time to join it
+	var workers sync.WaitGroup
+	results := make([]Wind, 5)
+	for idx := range results {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			results[idx], _, _ = cache.Coalesce(context.Background(), "milan", fetch)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	workers.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Got %d fetches, wanted 1", got)
+	}
+
+	for _, result := range results {
+		if result.Direction != "N" {
+			t.Errorf("Got %+v, wanted the shared value", result)
+		}
+	}
+
+	if _, found := cache.GetEntry("MILAN", time.Hour); !found {
+		t.Errorf("Got no entry, wanted the fetched value to be cached")
+	}
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache := InitCache()
+	cache.WindCache.AddEntry(Wind{Speed: "5"}, "45.46,9.19")
+	cache.GeoCache.AddEntry(Candidates{{Name: "Milan", Country: "IT"}}, "MILAN")
+
+	if err := cache.SaveSnapshot(path); err != nil {
+		t.Fatalf("Got %v, wanted nil", err)
+	}
+
+	restored := InitCache()
+	if err := restored.LoadSnapshot(path); err != nil {
+		t.Fatalf("Got %v, wanted nil", err)
+	}
+
+	wind, found := restored.WindCache.GetEntry("45.46,9.19", time.Hour)
+	if !found || wind.Speed != "5" {
+		t.Errorf("Got %v, wanted %v", wind, Wind{Speed: "5"})
+	}
+
+	candidates, found := restored.GeoCache.GetEntry("MILAN", time.Hour)
+	if !found || candidates[0].Name != "Milan" {
+		t.Errorf("Got %v, wanted %v", candidates, Candidates{{Name: "Milan", Country: "IT"}})
+	}
+}
+
+func TestCacheCoalesceCancel(t *testing.T) {
+	cache := Cache[Wind]{data: make(map[string]CacheEntity[Wind])}
+	release := make(chan struct{})
+
+	fetch := func(ctx context.Context) (Wind, error) {
+		select {
+		case <-release:
+			return Wind{Direction: "N"}, nil
+		case <-ctx.Done():
+			return Wind{}, ctx.Err()
+		}
+	}
+
+	// The first caller starts the fetch and gives up while it is pending
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, _, err := cache.Coalesce(leaderCtx, "milan", fetch)
+		leaderErr <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	type result struct {
+		wind Wind
+		err  error
+	}
+	waiter := make(chan result)
+	go func() {
+		wind, _, err := cache.Coalesce(context.Background(), "milan", func(ctx context.Context) (Wind, error) {
+			t.Error("Got a second fetch, wanted the pending one to be shared")
+			return Wind{}, nil
+		})
+		waiter <- result{wind, err}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Got %v, wanted the leader to give up", err)
+	}
+
+	// The shared fetch is not cancelled along with the leader
+	close(release)
+	if got := <-waiter; got.err != nil || got.wind.Direction != "N" {
+		t.Errorf("Got (%+v, %v), wanted the shared value", got.wind, got.err)
+	}
+}
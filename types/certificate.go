@@ -0,0 +1,98 @@
+package types
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertStore type, representing the TLS certificate served by Zephyr.
+// The certificate is read again whenever its files change on disk
+type CertStore struct {
+	mu          sync.RWMutex
+	certFile    string
+	keyFile     string
+	certificate *tls.Certificate
+	modTimes    [2]time.Time
+}
+
+func InitCertStore(certFile string, keyFile string) (*CertStore, error) {
+	store := &CertStore{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if _, err := store.Reload(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (store *CertStore) readModTimes() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for idx, path := range []string{store.certFile, store.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[idx] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+// Reload reads the certificate again if its files have changed, reporting
+// whether it has been replaced. On errors, the current certificate is kept
+func (store *CertStore) Reload() (bool, error) {
+	modTimes, err := store.readModTimes()
+	if err != nil {
+		return false, err
+	}
+
+	store.mu.RLock()
+	unchanged := store.certificate != nil && modTimes == store.modTimes
+	store.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(store.certFile, store.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.certificate = &certificate
+	store.modTimes = modTimes
+
+	return true, nil
+}
+
+// GetCertificate returns the current certificate, see tls.Config.GetCertificate
+func (store *CertStore) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.certificate, nil
+}
+
+// LoadCertPool reads a PEM bundle of certificate authorities
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no PEM certificates found in '%s'", path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate and its private key
+func writeCertificate(t *testing.T, dir string, commonName string, modTime time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+	os.Chtimes(certFile, modTime, modTime)
+	os.Chtimes(keyFile, modTime, modTime)
+
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, store *CertStore) string {
+	certificate, _ := store.GetCertificate(nil)
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.Subject.CommonName
+}
+
+func TestCertStoreReload(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	certFile, keyFile := writeCertificate(t, dir, "old", now.Add(-time.Minute))
+
+	store, err := InitCertStore(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Got %v, wanted nil", err)
+	}
+
+	if reloaded, _ := store.Reload(); reloaded {
+		t.Errorf("Got a reload, wanted none since files are unchanged")
+	}
+
+	writeCertificate(t, dir, "new", now)
+	if reloaded, err := store.Reload(); !reloaded || err != nil {
+		t.Fatalf("Got %v(%v), wanted a reload", reloaded, err)
+	}
+
+	if name := commonName(t, store); name != "new" {
+		t.Errorf("Got %s, wanted new", name)
+	}
+
+	// A broken certificate does not replace the current one
+	os.WriteFile(certFile, []byte("garbage"), 0o644)
+	if _, err := store.Reload(); err == nil {
+		t.Errorf("Got nil, wanted an error")
+	}
+
+	if name := commonName(t, store); name != "new" {
+		t.Errorf("Got %s, wanted new", name)
+	}
+}
@@ -1,9 +1,15 @@
 package types
 
-// The City data type, representing the name, the latitude and the longitude
-// of a location
+// The City data type, representing the name, the country, the state,
+// the latitude and the longitude of a location
 type City struct {
-	Name string  `json:"name"`
-	Lat  float64 `json:"lat"`
-	Lon  float64 `json:"lon"`
+	Name    string  `json:"name"`
+	Country string  `json:"country"`
+	State   string  `json:"state,omitempty"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
 }
+
+// The Candidates data type, representing every location
+// matching a geocoding query
+type Candidates []City
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// contextKey, representing the keys of values stored into a request context
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	requestLogKey
+)
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+// RequestLog, representing the details of a request collected
+// while serving it and reported by the access log. Every method
+// is a no-op on a nil log(e.g. requests without an access log)
+type RequestLog struct {
+	mu              sync.Mutex
+	city            string
+	cache           string
+	upstreamCalls   int
+	upstreamLatency time.Duration
+	upstreamOutcome string
+}
+
+func WithRequestLog(ctx context.Context, requestLog *RequestLog) context.Context {
+	return context.WithValue(ctx, requestLogKey, requestLog)
+}
+
+func GetRequestLog(ctx context.Context) *RequestLog {
+	requestLog, _ := ctx.Value(requestLogKey).(*RequestLog)
+
+	return requestLog
+}
+
+func (requestLog *RequestLog) SetCity(city string) {
+	if requestLog == nil {
+		return
+	}
+
+	requestLog.mu.Lock()
+	defer requestLog.mu.Unlock()
+
+	requestLog.city = city
+}
+
+// SetCache records the outcome of the cache lookup(i.e. 'hit', 'miss' or 'stale')
+func (requestLog *RequestLog) SetCache(outcome string) {
+	if requestLog == nil {
+		return
+	}
+
+	requestLog.mu.Lock()
+	defer requestLog.mu.Unlock()
+
+	requestLog.cache = outcome
+}
+
+// AddUpstreamCall records the latency and the outcome of an upstream call
+func (requestLog *RequestLog) AddUpstreamCall(latency time.Duration, outcome string) {
+	if requestLog == nil {
+		return
+	}
+
+	requestLog.mu.Lock()
+	defer requestLog.mu.Unlock()
+
+	requestLog.upstreamCalls++
+	requestLog.upstreamLatency += latency
+	requestLog.upstreamOutcome = outcome
+}
+
+// Attrs returns the collected details as structured log attributes
+func (requestLog *RequestLog) Attrs() []slog.Attr {
+	if requestLog == nil {
+		return nil
+	}
+
+	requestLog.mu.Lock()
+	defer requestLog.mu.Unlock()
+
+	var attrs []slog.Attr
+	if requestLog.city != "" {
+		attrs = append(attrs, slog.String("city", requestLog.city))
+	}
+	if requestLog.cache != "" {
+		attrs = append(attrs, slog.String("cache", requestLog.cache))
+	}
+	if requestLog.upstreamCalls > 0 {
+		attrs = append(attrs, slog.Group("upstream",
+			slog.Int("calls", requestLog.upstreamCalls),
+			slog.Float64("latency_ms", float64(requestLog.upstreamLatency.Microseconds())/1000),
+			slog.String("outcome", requestLog.upstreamOutcome),
+		))
+	}
+
+	return attrs
+}
@@ -14,5 +14,6 @@ type ForecastEntity struct {
 
 // The Forecast data type, representing a set of ForecastEntity
 type Forecast struct {
-    Forecast []ForecastEntity `json:"forecast"`
+	Forecast []ForecastEntity `json:"forecast"`
+	Location *City            `json:"location,omitempty"`
 }
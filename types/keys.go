@@ -0,0 +1,160 @@
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// APIKey data type, representing a client API key. The key itself
+// is never stored, only its SHA-256 hash
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Rate      uint      `json:"rate"`  // Requests per minute(0 means default)
+	Burst     uint      `json:"burst"` // Bucket capacity(0 means default)
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// KeyStore data type, representing the set of client API keys
+type KeyStore struct {
+	mu   sync.RWMutex
+	path string
+	keys map[string]APIKey // Indexed by hash
+}
+
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// InitKeyStore initializes the key store, loading the keys
+// from the given file(if any)
+func InitKeyStore(path string) (*KeyStore, error) {
+	store := &KeyStore{
+		path: path,
+		keys: make(map[string]APIKey),
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	content, err := readFile(path)
+	if err != nil {
+		return nil, err
+	} else if content == nil {
+		return store, nil
+	}
+
+	var keys []APIKey
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		store.keys[key.Hash] = key
+	}
+
+	return store, nil
+}
+
+func (store *KeyStore) save() error {
+	if store.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(store.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(store.path, content, 0o600)
+}
+
+func (store *KeyStore) list() []APIKey {
+	keys := make([]APIKey, 0, len(store.keys))
+	for _, key := range store.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys
+}
+
+func (store *KeyStore) List() []APIKey {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.list()
+}
+
+// Lookup returns the API key matching the given secret
+func (store *KeyStore) Lookup(secret string) (APIKey, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	key, isPresent := store.keys[hashKey(secret)]
+
+	return key, isPresent
+}
+
+// Create generates a new API key, returning its metadata and
+// its secret. The secret cannot be retrieved afterwards
+func (store *KeyStore) Create(name string, rate uint, burst uint) (APIKey, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return APIKey{}, "", err
+	}
+
+	secret := "zk_" + hex.EncodeToString(buf)
+	hash := hashKey(secret)
+	key := APIKey{
+		ID:        hash[:16],
+		Name:      name,
+		Hash:      hash,
+		Rate:      rate,
+		Burst:     burst,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.keys[hash] = key
+	if err := store.save(); err != nil {
+		delete(store.keys, hash)
+		return APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// Delete removes the API key with the given identifier
+func (store *KeyStore) Delete(id string) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for hash, key := range store.keys {
+		if key.ID == id {
+			delete(store.keys, hash)
+			if err := store.save(); err != nil {
+				store.keys[hash] = key
+				return false, err
+			}
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
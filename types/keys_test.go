@@ -0,0 +1,45 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyStoreDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := InitKeyStore(filepath.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, secret, err := store.Create("clock", 60, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Make the file unwritable by placing it under a regular file
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store.path = filepath.Join(blocker, "keys.json")
+
+	if deleted, err := store.Delete(key.ID); deleted || err == nil {
+		t.Errorf("Got (%t, %v), wanted the deletion to fail", deleted, err)
+	}
+
+	// Keys that cannot be deleted from the file are kept in memory as well
+	if _, isPresent := store.Lookup(secret); !isPresent {
+		t.Errorf("Got no key, wanted it to be restored")
+	}
+
+	store.path = filepath.Join(dir, "keys.json")
+	if deleted, err := store.Delete(key.ID); !deleted || err != nil {
+		t.Errorf("Got (%t, %v), wanted the key to be deleted", deleted, err)
+	}
+
+	if _, isPresent := store.Lookup(secret); isPresent {
+		t.Errorf("Got a deleted key")
+	}
+}
@@ -8,4 +8,5 @@ type Metrics struct {
 	DewPoint   string `json:"dewPoint"`
 	UvIndex    string `json:"uvIndex"`
 	Visibility string `json:"visibility"`
+	Location   *City  `json:"location,omitempty"`
 }
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Structure representing a cache entry within a snapshot
+type snapshotEntry[T CacheType] struct {
+	Element   T         `json:"element"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (cache *Cache[T]) Snapshot() (json.RawMessage, error) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	entries := make(map[string]snapshotEntry[T], len(cache.data))
+	for key, val := range cache.data {
+		entries[key] = snapshotEntry[T]{Element: val.element, Timestamp: val.timestamp}
+	}
+
+	return json.Marshal(entries)
+}
+
+// Restore adds the entries of a snapshot to the cache, without
+// replacing entries that have been fetched in the meantime
+func (cache *Cache[T]) Restore(snapshot json.RawMessage) error {
+	var entries map[string]snapshotEntry[T]
+	if err := json.Unmarshal(snapshot, &entries); err != nil {
+		return err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for key, val := range entries {
+		if current, isPresent := cache.data[key]; isPresent && current.timestamp.After(val.Timestamp) {
+			continue
+		}
+
+		cache.data[key] = CacheEntity[T]{element: val.Element, timestamp: val.Timestamp}
+	}
+
+	return nil
+}
+
+// SaveSnapshot writes the content of every cache to the given file
+func (caches *Caches) SaveSnapshot(path string) error {
+	snapshot := make(map[string]json.RawMessage)
+	for name, kind := range caches.Kinds(TimeToLive{}) {
+		content, err := kind.Cache.Snapshot()
+		if err != nil {
+			return err
+		}
+
+		snapshot[name] = content
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, content, 0o644)
+}
+
+// LoadSnapshot restores the content of every cache from the given file(if any)
+func (caches *Caches) LoadSnapshot(path string) error {
+	content, err := readFile(path)
+	if err != nil || content == nil {
+		return err
+	}
+
+	var snapshot map[string]json.RawMessage
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return err
+	}
+
+	for name, kind := range caches.Kinds(TimeToLive{}) {
+		if val, isPresent := snapshot[name]; isPresent {
+			if err := kind.Cache.Restore(val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SaveSnapshot writes the content of the database to the given file
+func (statDB *StatDB) SaveSnapshot(path string) error {
+	statDB.mu.RLock()
+	content, err := json.Marshal(statDB.db)
+	statDB.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, content, 0o644)
+}
+
+// LoadSnapshot restores the content of the database from the given file(if any).
+// The database is not ready to serve requests until the snapshot has been loaded
+func (statDB *StatDB) LoadSnapshot(path string) error {
+	statDB.loaded.Store(false)
+	defer statDB.loaded.Store(true)
+
+	content, err := readFile(path)
+	if err != nil || content == nil {
+		return err
+	}
+
+	var records map[string]Weather
+	if err := json.Unmarshal(content, &records); err != nil {
+		return err
+	}
+
+	statDB.mu.Lock()
+	defer statDB.mu.Unlock()
+
+	for key, record := range records {
+		if _, isPresent := statDB.db[key]; !isPresent {
+			statDB.db[key] = record
+		}
+	}
+
+	return nil
+}
@@ -3,23 +3,52 @@ package types
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // StatDB data type, representing a mapping between a location and its weather
 type StatDB struct {
-	db map[string]Weather
+	mu     sync.RWMutex
+	db     map[string]Weather
+	loaded atomic.Bool
 }
 
 func InitDB() *StatDB {
-	return &StatDB{
+	statDB := &StatDB{
 		db: make(map[string]Weather),
 	}
+	statDB.loaded.Store(true)
+
+	return statDB
+}
+
+// IsLoaded reports whether the database is ready to serve requests
+func (statDB *StatDB) IsLoaded() bool {
+	return statDB.loaded.Load()
+}
+
+// Size returns the number of records and the number of distinct locations
+func (statDB *StatDB) Size() (int, int) {
+	statDB.mu.RLock()
+	defer statDB.mu.RUnlock()
+
+	locations := make(map[string]struct{})
+	for key := range statDB.db {
+		_, location, _ := strings.Cut(key, "@")
+		locations[location] = struct{}{}
+	}
+
+	return len(statDB.db), len(locations)
 }
 
 func (statDB *StatDB) AddStatistic(cityName string, weather Weather) {
 	key := fmt.Sprintf("%s@%s", weather.Date.Date.Format("2006-01-02"), cityName)
 
+	statDB.mu.Lock()
+	defer statDB.mu.Unlock()
+
 	// Insert weather statistic into the database only if it isn't present
 	if _, isPresent := statDB.db[key]; isPresent {
 		return
@@ -32,9 +61,14 @@ func (statDB *StatDB) IsKeyInvalid(key string) bool {
 	// A key is invalid if it has less than 2 entries within the last 2 days
 	threshold := time.Now().AddDate(0, 0, -2)
 
+	statDB.mu.RLock()
+	defer statDB.mu.RUnlock()
+
 	var validKeys uint = 0
 	for storedKey, record := range statDB.db {
-		if !strings.HasSuffix(storedKey, key) {
+		// Match the whole location to avoid collisions between
+		// keys sharing the same suffix
+		if !strings.HasSuffix(storedKey, "@"+key) {
 			continue
 		}
 
@@ -54,8 +88,11 @@ func (statDB *StatDB) IsKeyInvalid(key string) bool {
 func (statDB *StatDB) GetCityStatistics(cityName string) []Weather {
 	result := make([]Weather, 0)
 
+	statDB.mu.RLock()
+	defer statDB.mu.RUnlock()
+
 	for key, record := range statDB.db {
-		if strings.HasSuffix(key, cityName) {
+		if strings.HasSuffix(key, "@"+cityName) {
 			result = append(result, record)
 		}
 	}
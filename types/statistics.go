@@ -10,12 +10,13 @@ type WeatherAnomaly struct {
 // The StatResult data type, representing weather statistics
 // of past meteorological events
 type StatResult struct {
-	Min     string            `json:"min"`
-	Max     string            `json:"max"`
-	Count   int               `json:"count"`
-	Mean    string            `json:"mean"`
-	StdDev  string            `json:"stdDev"`
-	Median  string            `json:"median"`
-	Mode    string            `json:"mode"`
-	Anomaly *[]WeatherAnomaly `json:"anomaly"`
+	Min      string            `json:"min"`
+	Max      string            `json:"max"`
+	Count    int               `json:"count"`
+	Mean     string            `json:"mean"`
+	StdDev   string            `json:"stdDev"`
+	Median   string            `json:"median"`
+	Mode     string            `json:"mode"`
+	Anomaly  *[]WeatherAnomaly `json:"anomaly"`
+	Location *City             `json:"location,omitempty"`
 }
@@ -0,0 +1,11 @@
+package types
+
+import "time"
+
+// The Sun data type, representing the sunrise and the sunset of a day.
+// Both are missing during the polar day and the polar night
+type Sun struct {
+	Sunrise   *time.Time `json:"sunrise"`
+	Sunset    *time.Time `json:"sunset"`
+	DayLength int        `json:"dayLength"` // Seconds of daylight
+}
@@ -0,0 +1,101 @@
+package types
+
+import "time"
+
+// Response types of the '/v2/' API. Unlike the v1 types, measures are
+// numbers expressed in the units listed by the response, while dates
+// are encoded in the ISO 8601 format
+
+// The UnitsV2 data type, representing the units of measurement of a response
+type UnitsV2 struct {
+	Temperature string `json:"temperature"`
+	Speed       string `json:"speed"`
+	Pressure    string `json:"pressure"`
+	Distance    string `json:"distance"`
+}
+
+// The WeatherV2 data type, representing the weather of a certain location
+type WeatherV2 struct {
+	Date        time.Time `json:"date"`
+	Temperature float64   `json:"temperature"`
+	Condition   string    `json:"condition"`
+	FeelsLike   float64   `json:"feelsLike"`
+	Emoji       string    `json:"emoji"`
+	Units       UnitsV2   `json:"units"`
+	Location    *City     `json:"location,omitempty"`
+}
+
+// The MetricsV2 data type, representing the humidity(%), pressure and
+// similar miscellaneous values
+type MetricsV2 struct {
+	Humidity   int     `json:"humidity"`
+	Pressure   int     `json:"pressure"`
+	DewPoint   float64 `json:"dewPoint"`
+	UvIndex    int     `json:"uvIndex"`
+	Visibility float64 `json:"visibility"`
+	Units      UnitsV2 `json:"units"`
+	Location   *City   `json:"location,omitempty"`
+}
+
+// The WindV2 data type, representing the wind at a certain time
+type WindV2 struct {
+	Arrow     string  `json:"arrow"`
+	Direction string  `json:"direction"`
+	Speed     float64 `json:"speed"`
+}
+
+// The CurrentWindV2 data type, representing the wind of a certain location
+type CurrentWindV2 struct {
+	WindV2
+	Units    UnitsV2 `json:"units"`
+	Location *City   `json:"location,omitempty"`
+}
+
+// The ForecastEntityV2 data type, representing the weather forecast
+// of a single day
+type ForecastEntityV2 struct {
+	Date      time.Time `json:"date"`
+	Min       float64   `json:"min"`
+	Max       float64   `json:"max"`
+	Condition string    `json:"condition"`
+	Emoji     string    `json:"emoji"`
+	FeelsLike float64   `json:"feelsLike"`
+	Wind      WindV2    `json:"wind"`
+}
+
+// The ForecastV2 data type, representing a set of ForecastEntityV2
+type ForecastV2 struct {
+	Forecast []ForecastEntityV2 `json:"forecast"`
+	Units    UnitsV2            `json:"units"`
+	Location *City              `json:"location,omitempty"`
+}
+
+// The MoonV2 data type, representing the moon phase,
+// the moon phase icon and the moon progress(%)
+type MoonV2 struct {
+	Icon       string `json:"icon"`
+	Phase      string `json:"phase"`
+	Percentage int    `json:"percentage"`
+}
+
+// The WeatherAnomalyV2 data type, representing
+// skewed meteorological events
+type WeatherAnomalyV2 struct {
+	Date        time.Time `json:"date"`
+	Temperature float64   `json:"temperature"`
+}
+
+// The StatResultV2 data type, representing weather statistics
+// of past meteorological events
+type StatResultV2 struct {
+	Min       float64            `json:"min"`
+	Max       float64            `json:"max"`
+	Count     int                `json:"count"`
+	Mean      float64            `json:"mean"`
+	StdDev    float64            `json:"stdDev"`
+	Median    float64            `json:"median"`
+	Mode      float64            `json:"mode"`
+	Anomalies []WeatherAnomalyV2 `json:"anomalies"`
+	Units     UnitsV2            `json:"units"`
+	Location  *City              `json:"location,omitempty"`
+}
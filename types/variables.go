@@ -1,7 +1,21 @@
 package types
 
-// Variables type, representing values read from environment variables
+import "time"
+
+// TimeToLive type, representing the cache time-to-live of each data kind
+type TimeToLive struct {
+	Weather   time.Duration
+	Metrics   time.Duration
+	Wind      time.Duration
+	Forecast  time.Duration
+	Moon      time.Duration
+	Geocoding time.Duration
+}
+
+// Variables type, representing values read from the configuration
 type Variables struct {
-	Token      string
-	TimeToLive int8
+	Token           string
+	TimeToLive      TimeToLive
+	AdminToken      string
+	StrictGeocoding bool
 }
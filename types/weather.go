@@ -7,4 +7,5 @@ type Weather struct {
 	Condition   string     `json:"condition"`
 	FeelsLike   string     `json:"feelsLike"`
 	Emoji       string     `json:"emoji"`
+	Location    *City      `json:"location,omitempty"`
 }
@@ -5,4 +5,5 @@ type Wind struct {
 	Arrow     string `json:"arrow"`
 	Direction string `json:"direction"`
 	Speed     string `json:"speed"`
+	Location  *City  `json:"location,omitempty"`
 }